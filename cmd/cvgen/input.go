@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cv-generator/internal/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readCV loads a models.CV from path ("-" for stdin). inputFormat is "json"
+// or "yaml"; when empty it is taken from the file extension, and stdin is
+// treated as JSON if it starts with "{".
+func readCV(path, inputFormat string) (models.CV, error) {
+	var cv models.CV
+
+	var data []byte
+	var err error
+	if path == "-" {
+		path = "stdin"
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return cv, err
+	}
+
+	if inputFormat == "" {
+		inputFormat = detectInputFormat(path, data)
+	}
+
+	switch inputFormat {
+	case "json":
+	case "yaml":
+		if data, err = yamlToJSON(data); err != nil {
+			return cv, fmt.Errorf("%s: invalid YAML: %w", path, err)
+		}
+	default:
+		return cv, fmt.Errorf("unsupported input format %q (use json or yaml)", inputFormat)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cv); err != nil {
+		return cv, fmt.Errorf("%s: invalid CV document: %w", path, err)
+	}
+	return cv, nil
+}
+
+func detectInputFormat(path string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return "json"
+	}
+	return "yaml"
+}
+
+// yamlToJSON converts a YAML document to JSON so it is decoded with the same
+// field names as the API. Scalars are kept as written, so dates, bare years
+// such as 2020, phone numbers and postal codes need no quotes; only the
+// fields in yamlTypedFields are decoded as numbers or booleans.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return []byte("{}"), nil
+	}
+	value, err := yamlValue(doc.Content[0], false)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// yamlTypedFields are the models.CV fields that are not strings
+var yamlTypedFields = map[string]bool{"fitPages": true, "hidden": true}
+
+// yamlValue converts node to a value for json.Marshal. typed is set for the
+// value of a yamlTypedFields key; other scalars become strings.
+func yamlValue(node *yaml.Node, typed bool) (interface{}, error) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		m := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			value, err := yamlValue(node.Content[i+1], yamlTypedFields[key])
+			if err != nil {
+				return nil, err
+			}
+			m[key] = value
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		list := make([]interface{}, 0, len(node.Content))
+		for _, child := range node.Content {
+			value, err := yamlValue(child, false)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return list, nil
+	case yaml.AliasNode:
+		return yamlValue(node.Alias, typed)
+	case yaml.ScalarNode:
+		if node.ShortTag() == "!!null" {
+			return nil, nil
+		}
+		if !typed {
+			return node.Value, nil
+		}
+		switch node.ShortTag() {
+		case "!!bool":
+			var b bool
+			err := node.Decode(&b)
+			return b, err
+		// Decoded rather than copied, so forms such as 0x1F and 1_000
+		// become valid JSON numbers
+		case "!!int":
+			var n int64
+			if err := node.Decode(&n); err != nil {
+				return nil, fmt.Errorf("line %d: %w", node.Line, err)
+			}
+			return n, nil
+		case "!!float":
+			var f float64
+			if err := node.Decode(&f); err != nil {
+				return nil, fmt.Errorf("line %d: %w", node.Line, err)
+			}
+			if math.IsInf(f, 0) || math.IsNaN(f) {
+				return nil, fmt.Errorf("line %d: %s is not a valid number", node.Line, node.Value)
+			}
+			return f, nil
+		default:
+			return node.Value, nil
+		}
+	default:
+		return nil, fmt.Errorf("line %d: unsupported YAML node", node.Line)
+	}
+}
+
+func marshalIndent(v interface{}) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestYAMLToJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"decimal", "fitPages: 2", `{"fitPages":2}`},
+		{"hexadecimal", "fitPages: 0x1F", `{"fitPages":31}`},
+		{"octal", "fitPages: 0o17", `{"fitPages":15}`},
+		{"underscores", "fitPages: 1_000", `{"fitPages":1000}`},
+		{"float", "fitPages: 2.0", `{"fitPages":2}`},
+		{"bare year", "startDate: 2020", `{"startDate":"2020"}`},
+		{"year in a list", "experience: [{endDate: 2021}]", `{"experience":[{"endDate":"2021"}]}`},
+		{"full date", "date: 2020-01-15", `{"date":"2020-01-15"}`},
+		{"phone", "personalInfo: {phone: 600123456}", `{"personalInfo":{"phone":"600123456"}}`},
+		{"phone with leading zero", "personalInfo: {phone: 0612345678}", `{"personalInfo":{"phone":"0612345678"}}`},
+		{"credential id", "certifications: [{credentialId: 12345}]", `{"certifications":[{"credentialId":"12345"}]}`},
+		{"postal code", "personalInfo: {location: 08001}", `{"personalInfo":{"location":"08001"}}`},
+		{"number-like name", "skills: [{name: 1e3}, {name: true}]", `{"skills":[{"name":"1e3"},{"name":"true"}]}`},
+		{"string list", "experience: [{highlights: [2020, 0x1F]}]", `{"experience":[{"highlights":["2020","0x1F"]}]}`},
+		{"bool", "layout: [{section: skills, hidden: true}]", `{"layout":[{"hidden":true,"section":"skills"}]}`},
+		{"alias of a typed field", "x: &n 2\nfitPages: *n", `{"fitPages":2,"x":"2"}`},
+		{"null", "phone: ~", `{"phone":null}`},
+		{"empty", "", `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := yamlToJSON([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("yamlToJSON(%q) error = %v", tt.input, err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("yamlToJSON(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYAMLToJSONInvalidNumber(t *testing.T) {
+	if _, err := yamlToJSON([]byte("fitPages: .inf")); err == nil {
+		t.Error("yamlToJSON(.inf) error = nil, want an error")
+	}
+}
+
+func TestReadCVYAMLStrings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cv.yaml")
+	doc := `personalInfo:
+  fullName: Zofia Nowak
+  phone: 600123456
+certifications:
+  - name: CKA
+    credentialId: 12345
+fitPages: 1
+`
+	if err := os.WriteFile(path, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cv, err := readCV(path, "")
+	if err != nil {
+		t.Fatalf("readCV() error = %v", err)
+	}
+	if cv.PersonalInfo.Phone != "600123456" || cv.Certifications[0].CredentialID != "12345" || cv.FitPages != 1 {
+		t.Errorf("readCV() = %+v", cv)
+	}
+}
@@ -0,0 +1,224 @@
+// Command cvgen renders CV documents without running the web server, so CVs
+// kept in a repository can be built from scripts:
+//
+//	cvgen render cv.json -o cv.pdf
+//	cvgen render -theme modern -lang es cv.yaml -o cv.pdf
+//	cat cv.json | cvgen render -format html > cv.html
+//	cvgen render cv.json -o cv.docx
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cv-generator/internal/logging"
+	"cv-generator/internal/models"
+	"cv-generator/internal/services"
+)
+
+const usage = `usage: cvgen render [flags] [input]
+
+Renders a CV read from input (a .json, .yaml or .yml file, or "-" / nothing
+for stdin) and writes the result to -o (stdout by default).
+
+Flags:
+`
+
+// Output formats supported by the render command
+var formats = map[string]string{
+	"pdf":        ".pdf",
+	"html":       ".html",
+	"docx":       ".docx",
+	"txt":        ".txt",
+	"md":         ".md",
+	"tex":        ".tex",
+	"jsonresume": ".json",
+	"europass":   ".xml",
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "--help" || os.Args[1] == "help" {
+		fmt.Fprint(os.Stderr, usage)
+		newRenderFlags(&renderOptions{}).PrintDefaults()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "render":
+		if err := render(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "cvgen: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "cvgen: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
+
+type renderOptions struct {
+	output      string
+	format      string
+	inputFormat string
+	language    string
+	theme       string
+	pageSize    string
+	fitPages    int
+	verbose     bool
+}
+
+func newRenderFlags(opts *renderOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	fs.StringVar(&opts.output, "o", "-", `output file, "-" for stdout`)
+	fs.StringVar(&opts.format, "format", "", "output format: pdf, html, docx, txt, md, tex, jsonresume or europass (default: from the -o extension, else pdf)")
+	fs.StringVar(&opts.inputFormat, "input-format", "", "input format: json or yaml (default: from the input extension)")
+	fs.StringVar(&opts.language, "lang", "", "CV language (en or es), overrides the document")
+	fs.StringVar(&opts.theme, "theme", "", fmt.Sprintf("PDF theme (%s), overrides the document", strings.Join(services.ThemeNames(), ", ")))
+	fs.StringVar(&opts.pageSize, "page-size", "", fmt.Sprintf("PDF page size (%s), overrides the theme", strings.Join(services.PageSizes(), ", ")))
+	fs.IntVar(&opts.fitPages, "fit-pages", 0, "shrink the PDF to at most this many pages, overrides the document")
+	fs.BoolVar(&opts.verbose, "v", false, "log rendering details to stderr")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func render(args []string) error {
+	var opts renderOptions
+	fs := newRenderFlags(&opts)
+
+	// Accept flags both before and after the input path
+	var inputs []string
+	for {
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			break
+		}
+		inputs = append(inputs, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(inputs) > 1 {
+		fs.Usage()
+		return fmt.Errorf("expected a single input, got %d", len(inputs))
+	}
+	input := "-"
+	if len(inputs) == 1 {
+		input = inputs[0]
+	}
+
+	level := slog.LevelWarn
+	if opts.verbose {
+		level = slog.LevelDebug
+	}
+	slog.SetDefault(logging.New(os.Stderr, logging.Options{Level: level, Redact: true}))
+
+	format := opts.format
+	if format == "" {
+		format = formatFromExtension(opts.output)
+	}
+	if _, ok := formats[format]; !ok {
+		return fmt.Errorf("unsupported format %q (use pdf, html, docx, txt, md, tex, jsonresume or europass)", format)
+	}
+
+	cv, err := readCV(input, opts.inputFormat)
+	if err != nil {
+		return err
+	}
+	if opts.language != "" {
+		cv.Language = opts.language
+	}
+	if opts.theme != "" {
+		cv.Theme = opts.theme
+	}
+	if opts.pageSize != "" {
+		cv.PageSize = opts.pageSize
+	}
+	if opts.fitPages != 0 {
+		cv.FitPages = opts.fitPages
+	}
+	if cv.Language == "" {
+		cv.Language = "en"
+	}
+
+	if violations := append(cv.Validate(), services.ValidateRenderOptions(cv)...); violations != nil {
+		for _, v := range violations {
+			field := v.Field
+			if field == "" {
+				field = "cv"
+			}
+			fmt.Fprintf(os.Stderr, "%s: %s\n", field, v.Message)
+		}
+		return fmt.Errorf("CV validation failed with %d violation(s)", len(violations))
+	}
+
+	out, err := renderFormat(cv, format)
+	if err != nil {
+		return err
+	}
+	return writeOutput(opts.output, out)
+}
+
+// formatFromExtension picks the output format matching the output file name
+func formatFromExtension(output string) string {
+	switch strings.ToLower(filepath.Ext(output)) {
+	case ".html", ".htm":
+		return "html"
+	case ".docx":
+		return "docx"
+	case ".txt":
+		return "txt"
+	case ".md", ".markdown":
+		return "md"
+	case ".tex":
+		return "tex"
+	case ".json":
+		return "jsonresume"
+	case ".xml":
+		return "europass"
+	default:
+		return "pdf"
+	}
+}
+
+func renderFormat(cv models.CV, format string) ([]byte, error) {
+	switch format {
+	case "html":
+		return services.NewHTMLService().GenerateCV(context.Background(), cv)
+	case "docx":
+		return services.NewDOCXService().GenerateCV(context.Background(), cv)
+	case "txt":
+		return services.NewTextService().GenerateCV(context.Background(), cv)
+	case "md":
+		return services.NewMarkdownService().GenerateCV(context.Background(), cv)
+	case "tex":
+		return services.NewLaTeXService().GenerateCV(context.Background(), cv)
+	case "jsonresume":
+		resume, warnings := services.NewJSONResumeService().Export(cv)
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s: %s\n", w.Field, w.Message)
+		}
+		return marshalIndent(resume)
+	case "europass":
+		europass := services.NewEuropassService()
+		doc, warnings := europass.Export(cv)
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s: %s\n", w.Field, w.Message)
+		}
+		return europass.EncodeXML(doc)
+	default:
+		return services.NewPDFService().GenerateCV(context.Background(), cv)
+	}
+}
+
+func writeOutput(path string, data []byte) error {
+	if path == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
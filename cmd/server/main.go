@@ -1,16 +1,18 @@
 package main
 
 import (
-	"log"
+	"log/slog"
 	"os"
 
 	"cv-generator/internal/config"
 	"cv-generator/internal/handlers"
+	"cv-generator/internal/logging"
+	"cv-generator/internal/storage"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/gofiber/template/html/v2"
 )
 
@@ -18,11 +20,26 @@ func main() {
 	// Load configuration
 	cfg := config.New()
 
+	// Structured logging; personal data is redacted unless LOG_VERBOSE is
+	// enabled on a local run
+	level := logging.ParseLevel(cfg.LogLevel)
+	if cfg.LogVerbose {
+		level = slog.LevelDebug
+	}
+	slog.SetDefault(logging.New(os.Stdout, logging.Options{
+		Level:  level,
+		JSON:   cfg.LogJSON,
+		Redact: !cfg.LogVerbose,
+	}))
+	if cfg.LogVerbose {
+		slog.Warn("verbose logging enabled: personal data is not redacted")
+	}
+
 	// Create template engine
 	engine := html.New("./web/templates", ".html")
 
 	// Disable reload in production, enable in development
-	if os.Getenv("RENDER") != "" {
+	if cfg.Production {
 		engine.Reload(false) // Production mode
 	} else {
 		engine.Reload(true) // Development mode
@@ -37,19 +54,53 @@ func main() {
 
 	// Middleware
 	app.Use(recover.New())
-	app.Use(logger.New())
+	app.Use(requestid.New())
+	app.Use(handlers.RequestLogger())
 	app.Use(cors.New())
 
 	// Static files
 	app.Static("/static", "./web/static")
 
+	// Open CV storage
+	if cfg.DatabasePath == "" {
+		slog.Error("DATABASE_PATH must be set to a file on a persistent disk in production")
+		os.Exit(1)
+	}
+	repo, err := storage.NewSQLiteRepository(cfg.DatabasePath)
+	if err != nil {
+		slog.Error("failed to open database", "path", cfg.DatabasePath, "error", err)
+		os.Exit(1)
+	}
+	defer repo.Close()
+
 	// Initialize handlers
-	cvHandler := handlers.NewCVHandler()
+	cvHandler := handlers.NewCVHandler(repo)
 
 	// Routes
 	app.Get("/", cvHandler.Home)
 	app.Post("/generate", cvHandler.GeneratePDF)
 	app.Get("/preview", cvHandler.Preview)
+	app.Post("/preview", cvHandler.Preview)
+
+	// JSON API
+	api := app.Group("/api/v1")
+	api.Post("/cv/render", cvHandler.RenderPDF)
+	api.Get("/themes", cvHandler.Themes)
+	api.Get("/cv/html", cvHandler.RenderHTML)
+	api.Post("/cv/html", cvHandler.RenderHTML)
+	api.Post("/import/jsonresume", cvHandler.ImportJSONResume)
+	api.Get("/export/jsonresume", cvHandler.ExportJSONResume)
+	api.Post("/export/jsonresume", cvHandler.ExportJSONResume)
+	api.Post("/import/europass", cvHandler.ImportEuropass)
+	api.Get("/export/europass", cvHandler.ExportEuropass)
+	api.Post("/export/europass", cvHandler.ExportEuropass)
+	api.Post("/import/linkedin", cvHandler.ImportLinkedIn)
+	api.Post("/cvs", cvHandler.CreateCV)
+	api.Get("/cvs", cvHandler.ListCVs)
+	api.Get("/cvs/:id", cvHandler.GetCV)
+	api.Put("/cvs/:id", cvHandler.UpdateCV)
+	api.Delete("/cvs/:id", cvHandler.DeleteCV)
+	api.Get("/cvs/:id/pdf", cvHandler.StoredCVPDF)
 
 	// Health check
 	app.Get("/health", func(c *fiber.Ctx) error {
@@ -62,6 +113,9 @@ func main() {
 	})
 
 	// Start server
-	log.Printf("🚀 Server starting on port %s", cfg.Port)
-	log.Fatal(app.Listen(":" + cfg.Port))
+	slog.Info("server starting", "port", cfg.Port)
+	if err := app.Listen(":" + cfg.Port); err != nil {
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
+	}
 }
@@ -3,7 +3,14 @@ package config
 import "os"
 
 type Config struct {
-	Port string
+	Port         string
+	DatabasePath string // empty in production unless DATABASE_PATH is set
+	Production   bool   // running on Render
+
+	// Logging
+	LogLevel   string // debug, info, warn or error
+	LogJSON    bool   // JSON lines instead of text; the default in production
+	LogVerbose bool   // debug level with personal data unredacted; ignored in production
 }
 
 func New() *Config {
@@ -12,7 +19,34 @@ func New() *Config {
 		port = "3000"
 	}
 
+	production := os.Getenv("RENDER") != ""
+
+	// The working directory is wiped on every Render deploy, so production
+	// has no default and the path must point to a persistent disk
+	databasePath := os.Getenv("DATABASE_PATH")
+	if databasePath == "" && !production {
+		databasePath = "cv-generator.db"
+	}
+
+	logLevel := os.Getenv("LOG_LEVEL")
+	if logLevel == "" {
+		logLevel = "info"
+	}
+
+	logJSON := production
+	switch os.Getenv("LOG_FORMAT") {
+	case "json":
+		logJSON = true
+	case "text":
+		logJSON = false
+	}
+
 	return &Config{
-		Port: port,
+		Port:         port,
+		DatabasePath: databasePath,
+		Production:   production,
+		LogLevel:     logLevel,
+		LogJSON:      logJSON,
+		LogVerbose:   os.Getenv("LOG_VERBOSE") == "true" && !production,
 	}
 }
@@ -1,24 +1,50 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"log"
-	"time"
+	"log/slog"
+	"strconv"
+	"strings"
 
 	"cv-generator/internal/models"
 	"cv-generator/internal/services"
+	"cv-generator/internal/storage"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// cvRenderer is implemented by the services that render a CV in one format
+type cvRenderer interface {
+	GenerateCV(ctx context.Context, cv models.CV) ([]byte, error)
+}
+
 type CVHandler struct {
-	pdfService *services.PDFService
+	pdfService        *services.PDFService
+	htmlService       *services.HTMLService
+	docxService       *services.DOCXService
+	textService       *services.TextService
+	markdownService   *services.TextService
+	latexService      *services.LaTeXService
+	jsonResumeService *services.JSONResumeService
+	europassService   *services.EuropassService
+	linkedInService   *services.LinkedInService
+	repo              storage.CVRepository
 }
 
-func NewCVHandler() *CVHandler {
+func NewCVHandler(repo storage.CVRepository) *CVHandler {
 	return &CVHandler{
-		pdfService: services.NewPDFService(),
+		pdfService:        services.NewPDFService(),
+		htmlService:       services.NewHTMLService(),
+		docxService:       services.NewDOCXService(),
+		textService:       services.NewTextService(),
+		markdownService:   services.NewMarkdownService(),
+		latexService:      services.NewLaTeXService(),
+		jsonResumeService: services.NewJSONResumeService(),
+		europassService:   services.NewEuropassService(),
+		linkedInService:   services.NewLinkedInService(),
+		repo:              repo,
 	}
 }
 
@@ -29,115 +55,192 @@ func (h *CVHandler) Home(c *fiber.Ctx) error {
 }
 
 func (h *CVHandler) GeneratePDF(c *fiber.Ctx) error {
-	log.Println("🚀 Starting PDF generation...")
-
-	var cv models.CV
-
-	// Parse form data
-	log.Println("📝 Parsing personal info...")
-	personalInfo := models.PersonalInfo{
-		FullName: c.FormValue("fullName"),
-		Email:    c.FormValue("email"),
-		Phone:    c.FormValue("phone"),
-		Location: c.FormValue("location"),
-		LinkedIn: c.FormValue("linkedin"),
-		GitHub:   c.FormValue("github"),
-		Website:  c.FormValue("website"),
-		Summary:  c.FormValue("summary"),
-	}
-	log.Printf("✅ Personal info parsed: %+v", personalInfo)
-
-	// Parse education (JSON array)
-	log.Println("🎓 Parsing education data...")
-	educationJSON := c.FormValue("education")
-	log.Printf("📋 Education JSON received: %s", educationJSON)
-	var education []models.Education
-	if educationJSON != "" {
-		if err := json.Unmarshal([]byte(educationJSON), &education); err != nil {
-			log.Printf("❌ Error parsing education: %v", err)
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid education data"})
-		}
-	}
-	log.Printf("✅ Education parsed: %+v", education)
-
-	// Parse experience (JSON array)
-	log.Println("💼 Parsing experience data...")
-	experienceJSON := c.FormValue("experience")
-	log.Printf("📋 Experience JSON received: %s", experienceJSON)
-	var experience []models.Experience
-	if experienceJSON != "" {
-		if err := json.Unmarshal([]byte(experienceJSON), &experience); err != nil {
-			log.Printf("❌ Error parsing experience: %v", err)
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid experience data"})
+	cv, decodeErrs := parseCV(c)
+	if decodeErrs != nil {
+		message := "Invalid CV data"
+		if field := decodeErrs[0].Field; field != "" {
+			if i := strings.IndexAny(field, ".["); i >= 0 {
+				field = field[:i]
+			}
+			message = fmt.Sprintf("Invalid %s data", field)
 		}
+		return sendError(c, fiber.StatusBadRequest, message, decodeErrs...)
 	}
-	log.Printf("✅ Experience parsed: %+v", experience)
-
-	// Parse skills (JSON array)
-	log.Println("🛠️ Parsing skills data...")
-	skillsJSON := c.FormValue("skills")
-	log.Printf("📋 Skills JSON received: %s", skillsJSON)
-	var skills []models.Skill
-	if skillsJSON != "" {
-		if err := json.Unmarshal([]byte(skillsJSON), &skills); err != nil {
-			log.Printf("❌ Error parsing skills: %v", err)
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid skills data"})
-		}
+
+	return h.sendDownload(c, cv)
+}
+
+// RenderPDF handles POST /api/v1/cv/render. It expects a models.CV document
+// as application/json and responds with the generated PDF, or with
+// ?format=docx, txt, md or tex a Word, plain text, Markdown or LaTeX
+// document.
+func (h *CVHandler) RenderPDF(c *fiber.Ctx) error {
+	if !isJSONRequest(c) {
+		return sendError(c, fiber.StatusUnsupportedMediaType, "Content-Type must be application/json")
 	}
-	log.Printf("✅ Skills parsed: %+v", skills)
-
-	// Parse languages (JSON array)
-	log.Println("🗣️ Parsing languages data...")
-	languagesJSON := c.FormValue("languages")
-	log.Printf("📋 Languages JSON received: %s", languagesJSON)
-	var languages []string
-	if languagesJSON != "" {
-		if err := json.Unmarshal([]byte(languagesJSON), &languages); err != nil {
-			log.Printf("❌ Error parsing languages: %v", err)
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid languages data"})
-		}
+
+	cv, decodeErrs := parseJSONCV(c.Body())
+	if decodeErrs != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid CV document", decodeErrs...)
 	}
-	log.Printf("✅ Languages parsed: %+v", languages)
 
-	// Parse UI language
-	uiLanguage := c.FormValue("language")
-	if uiLanguage == "" {
-		uiLanguage = "en" // default to English
+	return h.sendDownload(c, cv)
+}
+
+// sendDownload writes cv as an attachment in the format named by the
+// "format" query parameter: "pdf", the default, "docx", "txt", "md" or "tex"
+func (h *CVHandler) sendDownload(c *fiber.Ctx, cv models.CV) error {
+	switch format := c.Query("format", "pdf"); format {
+	case "pdf":
+		return h.sendPDF(c, cv, "attachment")
+	case "docx":
+		return h.sendDOCX(c, cv)
+	case "txt":
+		return h.sendText(c, cv, h.textService, services.TextContentType, "txt")
+	case "md":
+		return h.sendText(c, cv, h.markdownService, services.MarkdownContentType, "md")
+	case "tex":
+		return h.sendText(c, cv, h.latexService, services.LaTeXContentType, "tex")
+	default:
+		return sendError(c, fiber.StatusBadRequest, "Unsupported export format",
+			models.Violation{Field: "format", Code: models.CodeUnsupported, Message: fmt.Sprintf("%q is not one of pdf, docx, txt, md, tex", format)})
 	}
-	log.Printf("🌐 UI Language: %s", uiLanguage)
+}
 
-	cv = models.CV{
-		PersonalInfo: personalInfo,
-		Education:    education,
-		Experience:   experience,
-		Skills:       skills,
-		Languages:    languages,
-		Language:     uiLanguage,
-		CreatedAt:    time.Now(),
+// sendPDF validates cv, renders it and writes it to the response as a PDF.
+// disposition is "attachment" for downloads or "inline" for previews.
+// Invalid CVs are rejected with 422 and the list of violations.
+func (h *CVHandler) sendPDF(c *fiber.Ctx, cv models.CV, disposition string) error {
+	if violations := validateCV(cv); violations != nil {
+		return sendValidationError(c, violations)
 	}
-	log.Printf("✅ CV struct created: %+v", cv)
 
 	// Generate PDF
-	log.Println("📄 Starting PDF generation with native Go (gofpdf)...")
-	pdfBytes, err := h.pdfService.GenerateCV(cv)
+	ctx := c.UserContext()
+	pdfBytes, info, err := h.pdfService.Render(ctx, cv)
+	var fitErr *services.FitError
+	if errors.As(err, &fitErr) {
+		return sendValidationError(c, models.ValidationErrors{{
+			Field:   "fitPages",
+			Code:    models.CodeCannotFit,
+			Message: fitErr.Error(),
+		}})
+	}
 	if err != nil {
-		log.Printf("❌ PDF generation failed: %v", err)
-		return c.Status(500).JSON(fiber.Map{"error": fmt.Sprintf("Failed to generate PDF: %v", err)})
+		slog.ErrorContext(ctx, "PDF generation failed", "error", err)
+		return sendError(c, fiber.StatusInternalServerError, fmt.Sprintf("Failed to generate PDF: %v", err))
 	}
-	log.Printf("✅ PDF generated successfully, size: %d bytes", len(pdfBytes))
+	slog.InfoContext(ctx, "PDF generated", "cv", cv, "bytes", len(pdfBytes), "pages", info.Pages, "scale", info.Scale)
 
-	// Set headers for PDF download
-	filename := fmt.Sprintf("%s_CV_%s.pdf", personalInfo.FullName, time.Now().Format("2006-01-02"))
-	log.Printf("📁 Setting filename: %s", filename)
+	// Set headers for PDF download. The scale is below 1 when the CV was
+	// shrunk to fit fitPages.
+	c.Set("X-CV-Pages", strconv.Itoa(info.Pages))
+	c.Set("X-CV-Scale", strconv.FormatFloat(info.Scale, 'f', 2, 64))
 	c.Set("Content-Type", "application/pdf")
-	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	c.Set("Content-Disposition", fmt.Sprintf("%s; filename=\"%s\"", disposition, cvFilename(cv, "pdf")))
 
-	log.Println("🎉 PDF generation completed successfully!")
 	return c.Send(pdfBytes)
 }
 
-func (h *CVHandler) Preview(c *fiber.Ctx) error {
-	return c.Render("preview", fiber.Map{
-		"Title": "CV Preview",
+// sendDOCX validates cv and writes it to the response as a Word document
+// download. fitPages does not apply, since Word lays out the pages itself.
+func (h *CVHandler) sendDOCX(c *fiber.Ctx, cv models.CV) error {
+	if violations := validateCV(cv); violations != nil {
+		return sendValidationError(c, violations)
+	}
+
+	ctx := c.UserContext()
+	docxBytes, err := h.docxService.GenerateCV(ctx, cv)
+	if err != nil {
+		slog.ErrorContext(ctx, "DOCX generation failed", "error", err)
+		return sendError(c, fiber.StatusInternalServerError, fmt.Sprintf("Failed to generate DOCX: %v", err))
+	}
+
+	slog.InfoContext(ctx, "DOCX generated", "cv", cv, "bytes", len(docxBytes))
+
+	c.Set("Content-Type", services.DOCXContentType)
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", cvFilename(cv, "docx")))
+	return c.Send(docxBytes)
+}
+
+// sendText validates cv and writes it to the response as a download of a
+// text format: plain text, Markdown or LaTeX
+func (h *CVHandler) sendText(c *fiber.Ctx, cv models.CV, service cvRenderer, contentType, ext string) error {
+	if violations := validateCV(cv); violations != nil {
+		return sendValidationError(c, violations)
+	}
+
+	ctx := c.UserContext()
+	textBytes, err := service.GenerateCV(ctx, cv)
+	if err != nil {
+		slog.ErrorContext(ctx, "text generation failed", "format", ext, "error", err)
+		return sendError(c, fiber.StatusInternalServerError, fmt.Sprintf("Failed to generate %s: %v", ext, err))
+	}
+
+	slog.InfoContext(ctx, "text generated", "format", ext, "cv", cv, "bytes", len(textBytes))
+
+	c.Set("Content-Type", contentType)
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", cvFilename(cv, ext)))
+	return c.Send(textBytes)
+}
+
+// RenderHTML handles GET and POST /api/v1/cv/html. It renders the CV as a
+// self-contained HTML document that can be hosted or emailed.
+func (h *CVHandler) RenderHTML(c *fiber.Ctx) error {
+	cv, decodeErrs := parseRequestCV(c)
+	if decodeErrs != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid CV document", decodeErrs...)
+	}
+
+	return h.sendHTML(c, cv)
+}
+
+// sendHTML validates cv and writes it to the response as an inline HTML
+// document. Invalid CVs are rejected with 422 and the list of violations.
+func (h *CVHandler) sendHTML(c *fiber.Ctx, cv models.CV) error {
+	if violations := validateCV(cv); violations != nil {
+		return sendValidationError(c, violations)
+	}
+
+	ctx := c.UserContext()
+	htmlBytes, err := h.htmlService.GenerateCV(ctx, cv)
+	if err != nil {
+		slog.ErrorContext(ctx, "HTML generation failed", "error", err)
+		return sendError(c, fiber.StatusInternalServerError, fmt.Sprintf("Failed to generate HTML: %v", err))
+	}
+
+	slog.InfoContext(ctx, "HTML generated", "cv", cv, "bytes", len(htmlBytes))
+
+	c.Set("Content-Type", fiber.MIMETextHTMLCharsetUTF8)
+	c.Set("Content-Disposition", fmt.Sprintf("inline; filename=\"%s\"", cvFilename(cv, "html")))
+	return c.Send(htmlBytes)
+}
+
+// Themes handles GET /api/v1/themes and lists the built-in PDF themes
+func (h *CVHandler) Themes(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"default": services.DefaultThemeName,
+		"themes":  services.ThemeNames(),
 	})
 }
+
+// Preview handles GET and POST /preview. It accepts the same payload as
+// /generate (or the "cv" query parameter on GET) and renders the CV on the
+// server: as an HTML page by default, or with ?format=pdf as the exact PDF
+// that /generate would download, served inline.
+func (h *CVHandler) Preview(c *fiber.Ctx) error {
+	cv, decodeErrs := parseRequestCV(c)
+	if decodeErrs != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid CV document", decodeErrs...)
+	}
+
+	switch format := c.Query("format", "html"); format {
+	case "html":
+		return h.sendHTML(c, cv)
+	case "pdf":
+		return h.sendPDF(c, cv, "inline")
+	default:
+		return sendError(c, fiber.StatusBadRequest, "Unsupported preview format",
+			models.Violation{Field: "format", Code: models.CodeUnsupported, Message: fmt.Sprintf("%q is not one of html, pdf", format)})
+	}
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"cv-generator/internal/models"
+	"cv-generator/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ImportEuropass handles POST /api/v1/import/europass. It converts a
+// Europass XML or Europass JSON document into a models.CV that can prefill
+// the form.
+func (h *CVHandler) ImportEuropass(c *fiber.Ctx) error {
+	if !isJSONRequest(c) && !isXMLRequest(c) {
+		return sendError(c, fiber.StatusUnsupportedMediaType, "Content-Type must be application/json or application/xml")
+	}
+
+	cv, warnings, err := h.europassService.Import(c.Body())
+	if err != nil {
+		slog.WarnContext(c.UserContext(), "Europass import failed", "error", err)
+		code := models.CodeInvalidJSON
+		if isXMLRequest(c) {
+			code = models.CodeInvalidXML
+		}
+		return sendError(c, fiber.StatusBadRequest, "Invalid Europass document",
+			models.Violation{Code: code, Message: err.Error()})
+	}
+	normalizeCV(&cv)
+
+	slog.InfoContext(c.UserContext(), "Europass imported", "cv", cv, "warnings", len(warnings))
+	return c.JSON(fiber.Map{
+		"cv":       cv,
+		"warnings": nonNilWarnings(warnings),
+	})
+}
+
+// ExportEuropass handles GET and POST /api/v1/export/europass. It takes the
+// CV like ExportJSONResume. With format=xml the Europass XML document is
+// sent as a download and the number of warnings in the X-CV-Warnings
+// header; otherwise the response holds the Europass JSON document and the
+// warnings.
+func (h *CVHandler) ExportEuropass(c *fiber.Ctx) error {
+	cv, decodeErrs := parseRequestCV(c)
+	if decodeErrs != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid CV document", decodeErrs...)
+	}
+
+	doc, warnings := h.europassService.Export(cv)
+	ctx := c.UserContext()
+
+	switch format := c.Query("format", "json"); format {
+	case "json":
+		slog.InfoContext(ctx, "Europass exported", "format", format, "cv", cv, "warnings", len(warnings))
+		return c.JSON(fiber.Map{
+			"europass": services.EuropassDocument{SkillsPassport: &doc},
+			"warnings": nonNilWarnings(warnings),
+		})
+	case "xml":
+		out, err := h.europassService.EncodeXML(doc)
+		if err != nil {
+			slog.ErrorContext(ctx, "Europass XML encoding failed", "error", err)
+			return sendError(c, fiber.StatusInternalServerError, fmt.Sprintf("Failed to encode Europass XML: %v", err))
+		}
+		slog.InfoContext(ctx, "Europass exported", "format", format, "cv", cv, "warnings", len(warnings))
+
+		c.Set("Content-Type", fiber.MIMEApplicationXMLCharsetUTF8)
+		c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", cvFilename(cv, "xml")))
+		c.Set("X-CV-Warnings", strconv.Itoa(len(warnings)))
+		return c.Send(out)
+	default:
+		return sendError(c, fiber.StatusBadRequest, "Unsupported export format",
+			models.Violation{Field: "format", Code: models.CodeUnsupported, Message: fmt.Sprintf("%q is not one of json, xml", format)})
+	}
+}
+
+// isXMLRequest reports whether the request body is declared as XML
+func isXMLRequest(c *fiber.Ctx) bool {
+	contentType := strings.ToLower(string(c.Request().Header.ContentType()))
+	return strings.HasPrefix(contentType, fiber.MIMEApplicationXML) || strings.HasPrefix(contentType, fiber.MIMETextXML)
+}
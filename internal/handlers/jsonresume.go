@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"log/slog"
+
+	"cv-generator/internal/models"
+	"cv-generator/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ImportJSONResume handles POST /api/v1/import/jsonresume. It converts a
+// JSON Resume document into a models.CV that can prefill the form.
+func (h *CVHandler) ImportJSONResume(c *fiber.Ctx) error {
+	if !isJSONRequest(c) {
+		return sendError(c, fiber.StatusUnsupportedMediaType, "Content-Type must be application/json")
+	}
+
+	cv, warnings, err := h.jsonResumeService.Import(c.Body())
+	if err != nil {
+		slog.WarnContext(c.UserContext(), "JSON Resume import failed", "error", err)
+		return sendError(c, fiber.StatusBadRequest, "Invalid JSON Resume document",
+			models.Violation{Code: models.CodeInvalidJSON, Message: err.Error()})
+	}
+	normalizeCV(&cv)
+
+	slog.InfoContext(c.UserContext(), "JSON Resume imported", "cv", cv, "warnings", len(warnings))
+	return c.JSON(fiber.Map{
+		"cv":       cv,
+		"warnings": nonNilWarnings(warnings),
+	})
+}
+
+// ExportJSONResume handles GET and POST /api/v1/export/jsonresume. POST takes
+// the same payload as /generate; GET takes the CV as JSON in the "cv" query
+// parameter.
+func (h *CVHandler) ExportJSONResume(c *fiber.Ctx) error {
+	cv, decodeErrs := parseRequestCV(c)
+	if decodeErrs != nil {
+		return sendError(c, fiber.StatusBadRequest, "Invalid CV document", decodeErrs...)
+	}
+
+	resume, warnings := h.jsonResumeService.Export(cv)
+
+	slog.InfoContext(c.UserContext(), "JSON Resume exported", "cv", cv, "warnings", len(warnings))
+	return c.JSON(fiber.Map{
+		"resume":   resume,
+		"warnings": nonNilWarnings(warnings),
+	})
+}
+
+// nonNilWarnings makes an empty warning list encode as [] instead of null
+func nonNilWarnings(warnings []services.ConversionWarning) []services.ConversionWarning {
+	if warnings == nil {
+		return []services.ConversionWarning{}
+	}
+	return warnings
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+
+	"cv-generator/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ImportLinkedIn handles POST /api/v1/import/linkedin. It converts the ZIP
+// archive of a LinkedIn data export, uploaded as the "file" field of a
+// multipart form or sent as the request body, into a models.CV that can
+// prefill the form. Columns that were not imported are listed in the
+// warnings.
+func (h *CVHandler) ImportLinkedIn(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	contentType := strings.ToLower(string(c.Request().Header.ContentType()))
+
+	var archive []byte
+	switch {
+	case strings.HasPrefix(contentType, fiber.MIMEMultipartForm):
+		header, err := c.FormFile("file")
+		if err != nil {
+			return sendError(c, fiber.StatusBadRequest, "Missing LinkedIn export",
+				models.Violation{Field: "file", Code: models.CodeRequired, Message: "upload the ZIP archive in the file field"})
+		}
+		f, err := header.Open()
+		if err != nil {
+			slog.ErrorContext(ctx, "reading upload failed", "error", err)
+			return sendError(c, fiber.StatusInternalServerError, "Failed to read the upload")
+		}
+		defer f.Close()
+		if archive, err = io.ReadAll(f); err != nil {
+			slog.ErrorContext(ctx, "reading upload failed", "error", err)
+			return sendError(c, fiber.StatusInternalServerError, "Failed to read the upload")
+		}
+	case strings.HasPrefix(contentType, "application/zip"), strings.HasPrefix(contentType, fiber.MIMEOctetStream):
+		archive = c.Body()
+	default:
+		return sendError(c, fiber.StatusUnsupportedMediaType, "Content-Type must be multipart/form-data or application/zip")
+	}
+
+	cv, warnings, err := h.linkedInService.Import(archive)
+	if err != nil {
+		slog.WarnContext(ctx, "LinkedIn import failed", "error", err)
+		return sendError(c, fiber.StatusBadRequest, "Invalid LinkedIn export",
+			models.Violation{Field: "file", Code: models.CodeInvalidArchive, Message: err.Error()})
+	}
+	normalizeCV(&cv)
+
+	slog.InfoContext(ctx, "LinkedIn export imported", "cv", cv, "warnings", len(warnings))
+	return c.JSON(fiber.Map{
+		"cv":       cv,
+		"warnings": nonNilWarnings(warnings),
+	})
+}
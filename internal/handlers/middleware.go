@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"log/slog"
+	"time"
+
+	"cv-generator/internal/logging"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+)
+
+// RequestLogger stores the request ID set by Fiber's requestid middleware in
+// the user context, so log records written while handling the request carry
+// it, and writes one access log record per request. It replaces Fiber's
+// logger middleware. Only the path is logged: query strings may hold a whole
+// CV (see parseRequestCV).
+func RequestLogger() fiber.Handler {
+	return logRequest
+}
+
+func logRequest(c *fiber.Ctx) error {
+	start := time.Now()
+	id, _ := c.Locals(requestid.ConfigDefault.ContextKey).(string)
+	ctx := logging.WithRequestID(c.UserContext(), id)
+	c.SetUserContext(ctx)
+
+	err := c.Next()
+	if err != nil {
+		// Let the error handler set the status before logging it
+		if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+			c.Status(fiber.StatusInternalServerError)
+		}
+		err = nil
+	}
+
+	status := c.Response().StatusCode()
+	level := slog.LevelInfo
+	switch {
+	case status >= fiber.StatusInternalServerError:
+		level = slog.LevelError
+	case status >= fiber.StatusBadRequest:
+		level = slog.LevelWarn
+	}
+	slog.Log(ctx, level, "request",
+		"method", c.Method(),
+		"path", c.Path(),
+		"status", status,
+		"duration", time.Since(start),
+	)
+	return err
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"cv-generator/internal/models"
+	"cv-generator/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errorResponse is the JSON body returned for rejected requests. Details
+// lists the offending fields, using the same shape for decoding errors and
+// validation failures.
+type errorResponse struct {
+	Error   string             `json:"error"`
+	Details []models.Violation `json:"details,omitempty"`
+}
+
+func sendError(c *fiber.Ctx, status int, message string, details ...models.Violation) error {
+	return c.Status(status).JSON(errorResponse{Error: message, Details: details})
+}
+
+// validateCV runs models.CV.Validate and additionally checks the render
+// options whose allowed values are defined by the services package
+func validateCV(cv models.CV) models.ValidationErrors {
+	return append(cv.Validate(), services.ValidateRenderOptions(cv)...)
+}
+
+// sendValidationError rejects a CV that failed validateCV
+func sendValidationError(c *fiber.Ctx, violations models.ValidationErrors) error {
+	slog.InfoContext(c.UserContext(), "CV validation failed", "violations", len(violations))
+	return sendError(c, fiber.StatusUnprocessableEntity, "CV validation failed", violations...)
+}
+
+// isJSONRequest reports whether the request body is declared as JSON
+func isJSONRequest(c *fiber.Ctx) bool {
+	return strings.HasPrefix(strings.ToLower(string(c.Request().Header.ContentType())), fiber.MIMEApplicationJSON)
+}
+
+// parseCV reads a CV from the request body, accepting either a JSON document
+// or the form fields posted by the web UI
+func parseCV(c *fiber.Ctx) (models.CV, models.ValidationErrors) {
+	if isJSONRequest(c) {
+		return parseJSONCV(c.Body())
+	}
+	return parseFormCV(c)
+}
+
+// parseRequestCV reads a CV for endpoints that accept both GET and POST.
+// GET requests carry the CV as JSON in the "cv" query parameter; other
+// methods use the request body like parseCV.
+func parseRequestCV(c *fiber.Ctx) (models.CV, models.ValidationErrors) {
+	if c.Method() != fiber.MethodGet {
+		return parseCV(c)
+	}
+
+	raw := c.Query("cv")
+	if raw == "" {
+		return models.CV{}, models.ValidationErrors{{Field: "cv", Code: models.CodeRequired, Message: "query parameter is required"}}
+	}
+	return parseJSONCV([]byte(raw))
+}
+
+// cvFilename builds the download name for a rendered CV
+func cvFilename(cv models.CV, ext string) string {
+	return fmt.Sprintf("%s_CV_%s.%s", cv.PersonalInfo.FullName, time.Now().Format("2006-01-02"), ext)
+}
+
+// parseJSONCV decodes a models.CV document sent as application/json
+func parseJSONCV(body []byte) (models.CV, models.ValidationErrors) {
+	var cv models.CV
+
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return cv, models.ValidationErrors{{Code: models.CodeRequired, Message: "request body is empty"}}
+	}
+
+	if fieldErr := decodeJSON("", body, &cv); fieldErr != nil {
+		return cv, models.ValidationErrors{*fieldErr}
+	}
+
+	normalizeCV(&cv)
+	return cv, nil
+}
+
+// parseFormCV builds a models.CV from multipart or urlencoded form fields.
+// List sections are sent as JSON arrays inside their own form fields.
+func parseFormCV(c *fiber.Ctx) (models.CV, models.ValidationErrors) {
+	var cv models.CV
+
+	// Parse form data
+	cv.PersonalInfo = models.PersonalInfo{
+		FullName: c.FormValue("fullName"),
+		Email:    c.FormValue("email"),
+		Phone:    c.FormValue("phone"),
+		Location: c.FormValue("location"),
+		LinkedIn: c.FormValue("linkedin"),
+		GitHub:   c.FormValue("github"),
+		Website:  c.FormValue("website"),
+		Summary:  c.FormValue("summary"),
+	}
+
+	// Parse list sections (JSON arrays)
+	fields := []struct {
+		name string
+		dst  interface{}
+	}{
+		{"education", &cv.Education},
+		{"experience", &cv.Experience},
+		{"skills", &cv.Skills},
+		{"languages", &cv.Languages},
+		{"projects", &cv.Projects},
+		{"certifications", &cv.Certifications},
+		{"awards", &cv.Awards},
+		{"publications", &cv.Publications},
+		{"volunteer", &cv.Volunteer},
+		{"customSections", &cv.CustomSections},
+	}
+
+	for _, f := range fields {
+		raw := c.FormValue(f.name)
+		if raw == "" {
+			continue
+		}
+		if fieldErr := decodeJSON(f.name, []byte(raw), f.dst); fieldErr != nil {
+			slog.DebugContext(c.UserContext(), "invalid form field", "field", fieldErr.Field, "error", fieldErr.Message)
+			return cv, models.ValidationErrors{*fieldErr}
+		}
+	}
+
+	// Parse UI language and render options
+	cv.Language = c.FormValue("language")
+	cv.Theme = c.FormValue("theme")
+	cv.PageSize = c.FormValue("pageSize")
+	if raw := c.FormValue("fitPages"); raw != "" {
+		fitPages, err := strconv.Atoi(raw)
+		if err != nil {
+			return cv, models.ValidationErrors{{Field: "fitPages", Code: models.CodeInvalidType, Message: "must be a whole number"}}
+		}
+		cv.FitPages = fitPages
+	}
+
+	normalizeCV(&cv)
+	slog.DebugContext(c.UserContext(), "form CV parsed", "cv", cv)
+	return cv, nil
+}
+
+// decodeJSON unmarshals data into dst and converts decoding failures into a
+// Violation whose path is rooted at prefix
+func decodeJSON(prefix string, data []byte, dst interface{}) *models.Violation {
+	err := json.Unmarshal(data, dst)
+	if err == nil {
+		return nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		return &models.Violation{
+			Field:   prefix,
+			Code:    models.CodeInvalidJSON,
+			Message: fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset),
+		}
+	case errors.As(err, &typeErr):
+		return &models.Violation{
+			Field:   joinPath(prefix, typeErr.Field),
+			Code:    models.CodeInvalidType,
+			Message: fmt.Sprintf("expected %s but got %s", typeErr.Type.String(), typeErr.Value),
+		}
+	default:
+		return &models.Violation{Field: prefix, Code: models.CodeInvalidJSON, Message: err.Error()}
+	}
+}
+
+// joinPath joins two dotted JSON paths, rendering numeric segments
+// ("experience.2.endDate") as indices ("experience[2].endDate")
+func joinPath(prefix, field string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(field, ".") {
+		if part == "" {
+			continue
+		}
+		if _, err := strconv.Atoi(part); err == nil {
+			b.WriteString("[" + part + "]")
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(part)
+	}
+	field = b.String()
+
+	switch {
+	case prefix == "":
+		return field
+	case field == "":
+		return prefix
+	case strings.HasPrefix(field, "["):
+		return prefix + field
+	default:
+		return prefix + "." + field
+	}
+}
+
+// normalizeCV fills in defaults shared by every input format
+func normalizeCV(cv *models.CV) {
+	if cv.Language == "" {
+		cv.Language = "en" // default to English
+	}
+	if cv.CreatedAt.IsZero() {
+		cv.CreatedAt = time.Now()
+	}
+	if cv.UpdatedAt.IsZero() {
+		cv.UpdatedAt = cv.CreatedAt
+	}
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cv-generator/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// testApp returns an app with the routes that decode and validate a CV
+func testApp() *fiber.App {
+	h := NewCVHandler(nil)
+	app := fiber.New()
+	app.Post("/generate", h.GeneratePDF)
+	app.Post("/api/v1/cv/render", h.RenderPDF)
+	return app
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        string
+		status      int
+		field       string
+		code        string
+		message     string
+	}{
+		{
+			name: "malformed json", path: "/api/v1/cv/render",
+			contentType: fiber.MIMEApplicationJSON, body: `{"personalInfo": {"fullName": "Zofia",}}`,
+			status: fiber.StatusBadRequest, code: models.CodeInvalidJSON, message: "malformed JSON at offset 39",
+		},
+		{
+			name: "nested wrong type", path: "/api/v1/cv/render",
+			contentType: fiber.MIMEApplicationJSON, body: `{"experience": [{"startDate": "2020"}, {"startDate": 2021}]}`,
+			status: fiber.StatusBadRequest, field: "experience[1].startDate", code: models.CodeInvalidType, message: "expected string but got number",
+		},
+		{
+			name: "wrong content type", path: "/api/v1/cv/render",
+			contentType: fiber.MIMETextPlain, body: `{}`,
+			status: fiber.StatusUnsupportedMediaType,
+		},
+		{
+			name: "empty body", path: "/api/v1/cv/render",
+			contentType: fiber.MIMEApplicationJSON, body: " ",
+			status: fiber.StatusBadRequest, code: models.CodeRequired, message: "request body is empty",
+		},
+		{
+			name: "form field malformed json", path: "/generate",
+			contentType: fiber.MIMEApplicationForm, body: url.Values{"skills": {`[{"name": "Go"`}}.Encode(),
+			status: fiber.StatusBadRequest, field: "skills", code: models.CodeInvalidJSON,
+		},
+		{
+			name: "form field nested wrong type", path: "/generate",
+			contentType: fiber.MIMEApplicationForm, body: url.Values{"experience": {`[{}, {"startDate": 2021}]`}}.Encode(),
+			status: fiber.StatusBadRequest, field: "experience[1].startDate", code: models.CodeInvalidType,
+		},
+		{
+			name: "form fitPages", path: "/generate",
+			contentType: fiber.MIMEApplicationForm, body: url.Values{"fitPages": {"two"}}.Encode(),
+			status: fiber.StatusBadRequest, field: "fitPages", code: models.CodeInvalidType,
+		},
+	}
+	app := testApp()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			req.Header.Set(fiber.HeaderContentType, tt.contentType)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body := decodeErrorResponse(t, resp.Body)
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d (%+v)", resp.StatusCode, tt.status, body)
+			}
+			if tt.code == "" {
+				return
+			}
+			if len(body.Details) != 1 {
+				t.Fatalf("details = %+v, want one violation", body.Details)
+			}
+			got := body.Details[0]
+			if got.Field != tt.field || got.Code != tt.code || (tt.message != "" && got.Message != tt.message) {
+				t.Errorf("violation = %+v, want field %q, code %q, message %q", got, tt.field, tt.code, tt.message)
+			}
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	cv := models.CV{
+		PersonalInfo: models.PersonalInfo{Email: "not an email"},
+		Experience:   []models.Experience{{Company: "Acme", Position: "Engineer", StartDate: "2020", EndDate: "2019"}},
+		Theme:        "neon",
+	}
+	data, err := json.Marshal(cv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest("POST", "/api/v1/cv/render", strings.NewReader(string(data)))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := testApp().Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := decodeErrorResponse(t, resp.Body)
+	if resp.StatusCode != fiber.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422 (%+v)", resp.StatusCode, body)
+	}
+
+	var fields []string
+	for _, v := range body.Details {
+		fields = append(fields, v.Field)
+	}
+	want := []string{"personalInfo.fullName", "personalInfo.email", "experience[0].endDate", "theme"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("violation fields = %v, want %v", fields, want)
+	}
+}
+
+func TestJoinPath(t *testing.T) {
+	tests := []struct {
+		prefix string
+		field  string
+		want   string
+	}{
+		{"", "", ""},
+		{"", "personalInfo.email", "personalInfo.email"},
+		{"", "experience.2.endDate", "experience[2].endDate"},
+		{"experience", "", "experience"},
+		{"experience", "1.startDate", "experience[1].startDate"},
+		{"customSections", "0.entries.3.title", "customSections[0].entries[3].title"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix+"/"+tt.field, func(t *testing.T) {
+			if got := joinPath(tt.prefix, tt.field); got != tt.want {
+				t.Errorf("joinPath(%q, %q) = %q, want %q", tt.prefix, tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+// decodeErrorResponse reads the JSON body written by sendError
+func decodeErrorResponse(t *testing.T, r io.Reader) errorResponse {
+	t.Helper()
+	var body errorResponse
+	if err := json.NewDecoder(r).Decode(&body); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	return body
+}
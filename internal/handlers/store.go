@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"cv-generator/internal/models"
+	"cv-generator/internal/storage"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ownerHeader identifies the owner of stored CVs. There are no user accounts
+// yet, so it is an opaque value chosen by the client; requests without it
+// share the anonymous owner. It only keeps clients' CVs apart and is not
+// access control: anyone who sends another owner's value can read, replace
+// and delete that owner's CVs.
+const ownerHeader = "X-Owner-ID"
+
+func requestOwner(c *fiber.Ctx) string {
+	return c.Get(ownerHeader)
+}
+
+// CreateCV handles POST /api/v1/cvs. It validates and stores the CV sent as
+// application/json and responds with the stored document, including its ID.
+func (h *CVHandler) CreateCV(c *fiber.Ctx) error {
+	cv, ok, err := h.parseStoredCV(c)
+	if !ok {
+		return err
+	}
+
+	if err := h.repo.Create(c.UserContext(), requestOwner(c), &cv); err != nil {
+		return sendStorageError(c, err)
+	}
+
+	slog.InfoContext(c.UserContext(), "CV stored", "cv", cv)
+	c.Location("/api/v1/cvs/" + cv.ID)
+	return c.Status(fiber.StatusCreated).JSON(cv)
+}
+
+// ListCVs handles GET /api/v1/cvs and returns the owner's stored CVs
+func (h *CVHandler) ListCVs(c *fiber.Ctx) error {
+	cvs, err := h.repo.List(c.UserContext(), requestOwner(c))
+	if err != nil {
+		return sendStorageError(c, err)
+	}
+	return c.JSON(fiber.Map{"cvs": cvs})
+}
+
+// GetCV handles GET /api/v1/cvs/:id
+func (h *CVHandler) GetCV(c *fiber.Ctx) error {
+	cv, err := h.repo.Get(c.UserContext(), requestOwner(c), c.Params("id"))
+	if err != nil {
+		return sendStorageError(c, err)
+	}
+	return c.JSON(cv)
+}
+
+// UpdateCV handles PUT /api/v1/cvs/:id. The body replaces the stored
+// document; ID, owner and creation time are kept.
+func (h *CVHandler) UpdateCV(c *fiber.Ctx) error {
+	cv, ok, err := h.parseStoredCV(c)
+	if !ok {
+		return err
+	}
+
+	cv.ID = c.Params("id")
+	if err := h.repo.Update(c.UserContext(), requestOwner(c), &cv); err != nil {
+		return sendStorageError(c, err)
+	}
+
+	slog.InfoContext(c.UserContext(), "CV updated", "cv", cv)
+	return c.JSON(cv)
+}
+
+// DeleteCV handles DELETE /api/v1/cvs/:id
+func (h *CVHandler) DeleteCV(c *fiber.Ctx) error {
+	if err := h.repo.Delete(c.UserContext(), requestOwner(c), c.Params("id")); err != nil {
+		return sendStorageError(c, err)
+	}
+	slog.InfoContext(c.UserContext(), "CV deleted", "id", c.Params("id"))
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// StoredCVPDF handles GET /api/v1/cvs/:id/pdf and renders a stored CV
+func (h *CVHandler) StoredCVPDF(c *fiber.Ctx) error {
+	cv, err := h.repo.Get(c.UserContext(), requestOwner(c), c.Params("id"))
+	if err != nil {
+		return sendStorageError(c, err)
+	}
+	return h.sendPDF(c, cv, "attachment")
+}
+
+// parseStoredCV decodes and validates a CV sent to the storage endpoints.
+// Client-supplied ID, owner and timestamps are ignored. When ok is false the
+// error response has already been sent and err is the result of sending it.
+func (h *CVHandler) parseStoredCV(c *fiber.Ctx) (cv models.CV, ok bool, err error) {
+	if !isJSONRequest(c) {
+		return cv, false, sendError(c, fiber.StatusUnsupportedMediaType, "Content-Type must be application/json")
+	}
+
+	cv, decodeErrs := parseJSONCV(c.Body())
+	if decodeErrs != nil {
+		return cv, false, sendError(c, fiber.StatusBadRequest, "Invalid CV document", decodeErrs...)
+	}
+	if violations := validateCV(cv); violations != nil {
+		return cv, false, sendValidationError(c, violations)
+	}
+
+	return cv, true, nil
+}
+
+// sendStorageError maps repository errors to HTTP responses
+func sendStorageError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, storage.ErrNotFound) {
+		return sendError(c, fiber.StatusNotFound, "CV not found")
+	}
+	slog.ErrorContext(c.UserContext(), "storage error", "error", err)
+	return sendError(c, fiber.StatusInternalServerError, fmt.Sprintf("Storage error: %v", err))
+}
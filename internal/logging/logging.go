@@ -0,0 +1,104 @@
+// Package logging configures the structured logger shared by the server and
+// the services. Records carry the request ID from their context, and
+// personal data is redacted unless verbose output is explicitly enabled.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Redacted replaces the value of attributes that hold personal data
+const Redacted = "[REDACTED]"
+
+// piiKeys are the attribute keys masked by the redaction layer. They match
+// the JSON names of the models.PersonalInfo fields, which is how those
+// values appear in log records.
+var piiKeys = map[string]bool{
+	"fullName": true,
+	"email":    true,
+	"phone":    true,
+	"location": true,
+	"linkedin": true,
+	"github":   true,
+	"website":  true,
+	"summary":  true,
+}
+
+// Options configures New
+type Options struct {
+	Level  slog.Level
+	JSON   bool // JSON lines instead of key=value text
+	Redact bool // mask personal data, see piiKeys
+}
+
+// New builds a logger writing to w. Every record logged with a context
+// carrying a request ID (see WithRequestID) gets a request_id attribute.
+func New(w io.Writer, opts Options) *slog.Logger {
+	handlerOpts := &slog.HandlerOptions{Level: opts.Level}
+	if opts.Redact {
+		handlerOpts.ReplaceAttr = redact
+	}
+
+	var handler slog.Handler
+	if opts.JSON {
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	} else {
+		handler = slog.NewTextHandler(w, handlerOpts)
+	}
+	return slog.New(contextHandler{handler})
+}
+
+// ParseLevel converts a level name such as "debug" or "WARN" to a slog.Level,
+// falling back to info for empty or unknown names
+func ParseLevel(name string) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// redact masks attributes whose key names a personal data field, at any
+// group depth
+func redact(_ []string, a slog.Attr) slog.Attr {
+	if piiKeys[a.Key] && a.Value.Kind() != slog.KindGroup {
+		return slog.String(a.Key, Redacted)
+	}
+	return a
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context whose log records carry id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID stored in ctx, if any
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// contextHandler adds the request ID found in the record's context
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
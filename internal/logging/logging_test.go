@@ -0,0 +1,123 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"cv-generator/internal/models"
+)
+
+func TestRedaction(t *testing.T) {
+	cv := models.CV{
+		ID:       "cv-1",
+		Language: "es",
+		PersonalInfo: models.PersonalInfo{
+			FullName: "Zofia Nowak",
+			Email:    "zofia@example.com",
+			Phone:    "+48 600 123 456",
+			Location: "ul. Floriańska 12, Kraków",
+			LinkedIn: "https://www.linkedin.com/in/zofia",
+			GitHub:   "https://github.com/zofia",
+			Website:  "https://zofia.dev",
+			Summary:  "Backend engineer who loves payments",
+		},
+		Experience: []models.Experience{{Company: "Acme", Position: "Engineer", Description: "Secret project"}},
+	}
+	personal := []string{
+		"Zofia", "zofia@example.com", "600 123 456", "Floriańska", "linkedin.com/in/zofia",
+		"github.com/zofia", "zofia.dev", "loves payments", "Secret project",
+	}
+
+	tests := []struct {
+		name   string
+		opts   Options
+		hidden []string // must not appear in the output
+		shown  []string // must appear in the output
+	}{
+		{
+			name:   "redacted text",
+			opts:   Options{Redact: true},
+			hidden: personal,
+			shown:  []string{"request_id=req-1", "cv.personalInfo.email=" + Redacted, "cv.language=es", "cv.experience=1"},
+		},
+		{
+			name:   "redacted json",
+			opts:   Options{Redact: true, JSON: true},
+			hidden: personal,
+			shown:  []string{`"request_id":"req-1"`, `"email":"` + Redacted + `"`, `"language":"es"`, `"experience":1`},
+		},
+		{
+			name: "verbose",
+			opts: Options{Redact: false},
+			// The CV summary never includes entry content
+			hidden: []string{Redacted, "Secret project"},
+			shown:  []string{"request_id=req-1", "cv.personalInfo.fullName=\"Zofia Nowak\"", "zofia@example.com", "600 123 456", "Floriańska"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := New(&buf, tt.opts)
+			ctx := WithRequestID(context.Background(), "req-1")
+			logger.InfoContext(ctx, "PDF generated", "cv", cv, "pages", 2)
+
+			out := buf.String()
+			for _, s := range tt.hidden {
+				if strings.Contains(out, s) {
+					t.Errorf("log = %s\nwant no %q", out, s)
+				}
+			}
+			for _, s := range tt.shown {
+				if !strings.Contains(out, s) {
+					t.Errorf("log = %s\nwant %q", out, s)
+				}
+			}
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want bool
+	}{
+		{"with request id", WithRequestID(context.Background(), "req-1"), true},
+		{"without request id", context.Background(), false},
+		{"empty request id", WithRequestID(context.Background(), ""), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			// Attributes and groups added to the logger keep the request ID
+			logger := New(&buf, Options{}).With("component", "test").WithGroup("g")
+			logger.InfoContext(tt.ctx, "hello", "n", 1)
+			if got := strings.Contains(buf.String(), "request_id=req-1"); got != tt.want {
+				t.Errorf("log = %s, want request_id %v", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input string
+		want  slog.Level
+	}{
+		{"debug", slog.LevelDebug},
+		{" WARN ", slog.LevelWarn},
+		{"error", slog.LevelError},
+		{"", slog.LevelInfo},
+		{"verbose", slog.LevelInfo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := ParseLevel(tt.input); got != tt.want {
+				t.Errorf("ParseLevel(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"log/slog"
+	"time"
+)
 
 type PersonalInfo struct {
 	FullName string `json:"fullName" form:"fullName"`
@@ -14,19 +17,21 @@ type PersonalInfo struct {
 }
 
 type Education struct {
-	Institution string `json:"institution" form:"institution"`
-	Degree      string `json:"degree" form:"degree"`
-	StartDate   string `json:"startDate" form:"startDate"`
-	EndDate     string `json:"endDate" form:"endDate"`
-	Description string `json:"description" form:"description"`
+	Institution string   `json:"institution" form:"institution"`
+	Degree      string   `json:"degree" form:"degree"`
+	StartDate   string   `json:"startDate" form:"startDate"`
+	EndDate     string   `json:"endDate" form:"endDate"`
+	Description string   `json:"description" form:"description"`
+	Highlights  []string `json:"highlights,omitempty" form:"highlights"`
 }
 
 type Experience struct {
-	Company     string `json:"company" form:"company"`
-	Position    string `json:"position" form:"position"`
-	StartDate   string `json:"startDate" form:"startDate"`
-	EndDate     string `json:"endDate" form:"endDate"`
-	Description string `json:"description" form:"description"`
+	Company     string   `json:"company" form:"company"`
+	Position    string   `json:"position" form:"position"`
+	StartDate   string   `json:"startDate" form:"startDate"`
+	EndDate     string   `json:"endDate" form:"endDate"`
+	Description string   `json:"description" form:"description"`
+	Highlights  []string `json:"highlights,omitempty" form:"highlights"`
 }
 
 type Skill struct {
@@ -34,12 +39,123 @@ type Skill struct {
 	Level string `json:"level" form:"level"`
 }
 
+type Project struct {
+	Name         string   `json:"name" form:"name"`
+	Role         string   `json:"role" form:"role"`
+	URL          string   `json:"url" form:"url"`
+	Technologies []string `json:"technologies" form:"technologies"`
+	StartDate    string   `json:"startDate" form:"startDate"`
+	EndDate      string   `json:"endDate" form:"endDate"`
+	Description  string   `json:"description" form:"description"`
+}
+
+type Certification struct {
+	Name          string `json:"name" form:"name"`
+	Issuer        string `json:"issuer" form:"issuer"`
+	Date          string `json:"date" form:"date"`
+	ExpiryDate    string `json:"expiryDate" form:"expiryDate"`
+	CredentialID  string `json:"credentialId" form:"credentialId"`
+	CredentialURL string `json:"credentialUrl" form:"credentialUrl"`
+}
+
+type Award struct {
+	Title       string `json:"title" form:"title"`
+	Issuer      string `json:"issuer" form:"issuer"`
+	Date        string `json:"date" form:"date"`
+	Description string `json:"description" form:"description"`
+}
+
+type Publication struct {
+	Title       string `json:"title" form:"title"`
+	Publisher   string `json:"publisher" form:"publisher"`
+	Date        string `json:"date" form:"date"`
+	URL         string `json:"url" form:"url"`
+	Description string `json:"description" form:"description"`
+}
+
+type Volunteer struct {
+	Organization string `json:"organization" form:"organization"`
+	Role         string `json:"role" form:"role"`
+	StartDate    string `json:"startDate" form:"startDate"`
+	EndDate      string `json:"endDate" form:"endDate"`
+	Description  string `json:"description" form:"description"`
+}
+
+// CustomSection is a user-defined section such as "Talks" or "Patents". It
+// holds either free text in Content or a list of Entries, and its title is
+// printed as written, without translation.
+type CustomSection struct {
+	Title   string        `json:"title" form:"title"`
+	Content string        `json:"content,omitempty" form:"content"`
+	Entries []CustomEntry `json:"entries,omitempty" form:"entries"`
+}
+
+// CustomEntry is an item of a CustomSection. A StartDate without EndDate is
+// shown as a single date.
+type CustomEntry struct {
+	Title       string `json:"title" form:"title"`
+	Subtitle    string `json:"subtitle" form:"subtitle"`
+	StartDate   string `json:"startDate" form:"startDate"`
+	EndDate     string `json:"endDate" form:"endDate"`
+	Description string `json:"description" form:"description"`
+}
+
 type CV struct {
-	PersonalInfo PersonalInfo `json:"personalInfo"`
-	Education    []Education  `json:"education"`
-	Experience   []Experience `json:"experience"`
-	Skills       []Skill      `json:"skills"`
-	Languages    []string     `json:"languages"`
-	Language     string       `json:"language" form:"language"` // UI language (en/es)
-	CreatedAt    time.Time    `json:"createdAt"`
+	ID             string          `json:"id,omitempty"`    // set when the CV is stored
+	Owner          string          `json:"owner,omitempty"` // set when the CV is stored
+	PersonalInfo   PersonalInfo    `json:"personalInfo"`
+	Education      []Education     `json:"education"`
+	Experience     []Experience    `json:"experience"`
+	Skills         []Skill         `json:"skills"`
+	Languages      []Language      `json:"languages"`
+	Projects       []Project       `json:"projects,omitempty"`
+	Certifications []Certification `json:"certifications,omitempty"`
+	Awards         []Award         `json:"awards,omitempty"`
+	Publications   []Publication   `json:"publications,omitempty"`
+	Volunteer      []Volunteer     `json:"volunteer,omitempty"`
+	CustomSections []CustomSection `json:"customSections,omitempty"`
+	Layout         []SectionLayout `json:"layout,omitempty"`                   // section order, visibility and titles, see CV.Sections
+	Language       string          `json:"language" form:"language"`           // UI language (en/es)
+	Theme          string          `json:"theme,omitempty" form:"theme"`       // PDF theme name, empty for the default
+	PageSize       string          `json:"pageSize,omitempty" form:"pageSize"` // e.g. "A4" or "Letter", empty for the theme's size
+	FitPages       int             `json:"fitPages,omitempty" form:"fitPages"` // shrink the PDF to at most this many pages, 0 to disable
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+}
+
+// LogValue lists the personal details under their JSON names so the logging
+// redaction layer can mask them
+func (p PersonalInfo) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("fullName", p.FullName),
+		slog.String("email", p.Email),
+		slog.String("phone", p.Phone),
+		slog.String("location", p.Location),
+		slog.String("linkedin", p.LinkedIn),
+		slog.String("github", p.GitHub),
+		slog.String("website", p.Website),
+		slog.String("summary", p.Summary),
+	)
+}
+
+// LogValue summarizes a CV for log records: render options, personal details
+// (redacted by the logger) and entry counts instead of the full content
+func (cv CV) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", cv.ID),
+		slog.String("language", cv.Language),
+		slog.String("theme", cv.Theme),
+		slog.Int("fitPages", cv.FitPages),
+		slog.Any("personalInfo", cv.PersonalInfo),
+		slog.Int("experience", len(cv.Experience)),
+		slog.Int("education", len(cv.Education)),
+		slog.Int("skills", len(cv.Skills)),
+		slog.Int("languages", len(cv.Languages)),
+		slog.Int("projects", len(cv.Projects)),
+		slog.Int("certifications", len(cv.Certifications)),
+		slog.Int("awards", len(cv.Awards)),
+		slog.Int("publications", len(cv.Publications)),
+		slog.Int("volunteer", len(cv.Volunteer)),
+		slog.Int("customSections", len(cv.CustomSections)),
+	)
 }
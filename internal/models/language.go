@@ -0,0 +1,133 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Language levels of the Common European Framework of Reference (CEFR),
+// plus LevelNative for native speakers
+const (
+	LevelA1     = "A1"
+	LevelA2     = "A2"
+	LevelB1     = "B1"
+	LevelB2     = "B2"
+	LevelC1     = "C1"
+	LevelC2     = "C2"
+	LevelNative = "Native"
+)
+
+// LanguageLevels lists the accepted Language.Level values from lowest to
+// highest
+var LanguageLevels = []string{LevelA1, LevelA2, LevelB1, LevelB2, LevelC1, LevelC2, LevelNative}
+
+// Language is a spoken language and the candidate's proficiency in it
+type Language struct {
+	Name        string `json:"name" form:"name"`
+	Code        string `json:"code,omitempty" form:"code"`               // ISO 639-1 or 639-2 code, e.g. "en"
+	Level       string `json:"level,omitempty" form:"level"`             // one of LanguageLevels
+	Certificate string `json:"certificate,omitempty" form:"certificate"` // e.g. "TOEFL iBT 110" or "DELE C1"
+}
+
+// legacyLanguageSeparator joins name and level in the plain strings that
+// CV.Languages used to hold, as in the "Español - Nativo" form hint
+const legacyLanguageSeparator = " - "
+
+// UnmarshalJSON accepts both the Language object and the legacy plain
+// string forms such as "English - C1", "English (C1)" or "Español - Nativo"
+func (l *Language) UnmarshalJSON(data []byte) error {
+	var legacy string
+	if err := json.Unmarshal(data, &legacy); err == nil {
+		*l = ParseLegacyLanguage(legacy)
+		return nil
+	}
+
+	type language Language // no UnmarshalJSON method, avoids recursion
+	if err := json.Unmarshal(data, (*language)(l)); err != nil {
+		return err
+	}
+	// Accept "c1" or "Advanced" as well as the canonical "C1"
+	if level, ok := ParseLanguageLevel(l.Level); ok {
+		l.Level = level
+	}
+	return nil
+}
+
+// ParseLegacyLanguage converts a "Name - Level" or "Name (Level)" string into
+// a Language. The level is kept only when ParseLanguageLevel recognizes it;
+// otherwise the whole string becomes the name so nothing is lost.
+func ParseLegacyLanguage(value string) Language {
+	value = strings.TrimSpace(value)
+	name, level, found := strings.Cut(value, legacyLanguageSeparator)
+	if !found && strings.HasSuffix(value, ")") {
+		name, level, found = strings.Cut(strings.TrimSuffix(value, ")"), "(")
+	}
+	if !found {
+		return Language{Name: value}
+	}
+	if cefr, ok := ParseLanguageLevel(level); ok {
+		return Language{Name: strings.TrimSpace(name), Level: cefr}
+	}
+	return Language{Name: value}
+}
+
+// legacyLevels maps free-text proficiency descriptions, in English and
+// Spanish, to their closest CEFR level
+var legacyLevels = map[string]string{
+	"beginner":           LevelA1,
+	"principiante":       LevelA1,
+	"basic":              LevelA2,
+	"básico":             LevelA2,
+	"elementary":         LevelA2,
+	"elemental":          LevelA2,
+	"intermediate":       LevelB1,
+	"intermedio":         LevelB1,
+	"upper intermediate": LevelB2,
+	"intermedio alto":    LevelB2,
+	"advanced":           LevelC1,
+	"avanzado":           LevelC1,
+	"fluent":             LevelC1,
+	"fluido":             LevelC1,
+	"proficient":         LevelC2,
+	"maestría":           LevelC2,
+	"native":             LevelNative,
+	"nativo":             LevelNative,
+	"nativa":             LevelNative,
+	"bilingual":          LevelNative,
+	"bilingüe":           LevelNative,
+	"mother tongue":      LevelNative,
+	"lengua materna":     LevelNative,
+}
+
+// ParseLanguageLevel recognizes a CEFR level ("b2", "C1") or a common
+// proficiency description ("Advanced", "Nativo") and returns the matching
+// LanguageLevels value
+func ParseLanguageLevel(value string) (string, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, level := range LanguageLevels {
+		if strings.ToLower(level) == value {
+			return level, true
+		}
+	}
+	level, ok := legacyLevels[value]
+	return level, ok
+}
+
+// LevelRank returns the position of level in LanguageLevels starting at 1,
+// or 0 for an empty or unknown level. It is used to draw level scales.
+func LevelRank(level string) int {
+	for i, l := range LanguageLevels {
+		if l == level {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// String renders the language in the legacy "Name - Level" form
+func (l Language) String() string {
+	if l.Level == "" {
+		return l.Name
+	}
+	return l.Name + legacyLanguageSeparator + l.Level
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseLanguageLevel(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"C1", LevelC1, true},
+		{"c1", LevelC1, true},
+		{" b2 ", LevelB2, true},
+		{"native", LevelNative, true},
+		{"Advanced", LevelC1, true},
+		{"Nativo", LevelNative, true},
+		{"Upper Intermediate", LevelB2, true},
+		{"", "", false},
+		{"C3", "", false},
+		{"Fluent-ish", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := ParseLanguageLevel(tt.input)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("ParseLanguageLevel(%q) = %q, %v, want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseLegacyLanguage(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Language
+	}{
+		{"English - C1", Language{Name: "English", Level: LevelC1}},
+		{"English (C1)", Language{Name: "English", Level: LevelC1}},
+		{"Spanish - Native", Language{Name: "Spanish", Level: LevelNative}},
+		{"Español - Nativo", Language{Name: "Español", Level: LevelNative}},
+		{"German - b2", Language{Name: "German", Level: LevelB2}},
+		{"Italian (advanced)", Language{Name: "Italian", Level: LevelC1}},
+		{"French", Language{Name: "French"}},
+		{"  French  ", Language{Name: "French"}},
+		{"English - C3", Language{Name: "English - C3"}},
+		{"Portuguese (Brazil)", Language{Name: "Portuguese (Brazil)"}},
+		{"", Language{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := ParseLegacyLanguage(tt.input); got != tt.want {
+				t.Errorf("ParseLegacyLanguage(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLanguageUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Language
+		wantErr bool
+	}{
+		{"legacy string", `"English (C1)"`, Language{Name: "English", Level: LevelC1}, false},
+		{"legacy plain name", `"French"`, Language{Name: "French"}, false},
+		{"object", `{"name": "English", "code": "en", "level": "C1", "certificate": "CAE"}`,
+			Language{Name: "English", Code: "en", Level: LevelC1, Certificate: "CAE"}, false},
+		{"object with lower-case level", `{"name": "English", "level": "c2"}`, Language{Name: "English", Level: LevelC2}, false},
+		{"object with description level", `{"name": "Español", "level": "Nativo"}`, Language{Name: "Español", Level: LevelNative}, false},
+		// Left for Validate to report
+		{"object with invalid level", `{"name": "English", "level": "C3"}`, Language{Name: "English", Level: "C3"}, false},
+		{"wrong type", `42`, Language{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Language
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v, want error %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal(%s) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLanguageString(t *testing.T) {
+	tests := []struct {
+		language Language
+		want     string
+	}{
+		{Language{Name: "English", Level: LevelC1}, "English - C1"},
+		{Language{Name: "French"}, "French"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.language.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			// The legacy form parses back to the same language
+			if back := ParseLegacyLanguage(tt.want); back != tt.language {
+				t.Errorf("ParseLegacyLanguage(%q) = %+v, want %+v", tt.want, back, tt.language)
+			}
+		})
+	}
+}
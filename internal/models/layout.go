@@ -0,0 +1,74 @@
+package models
+
+// Section identifiers used in CV.Layout
+const (
+	SectionSummary        = "summary"
+	SectionExperience     = "experience"
+	SectionProjects       = "projects"
+	SectionEducation      = "education"
+	SectionCertifications = "certifications"
+	SectionPublications   = "publications"
+	SectionAwards         = "awards"
+	SectionVolunteer      = "volunteer"
+	SectionCustom         = "custom" // every entry of CV.CustomSections, in order
+	SectionSkills         = "skills"
+	SectionLanguages      = "languages"
+)
+
+// DefaultSectionOrder is the order used for sections not listed in CV.Layout
+var DefaultSectionOrder = []string{
+	SectionSummary,
+	SectionExperience,
+	SectionProjects,
+	SectionEducation,
+	SectionCertifications,
+	SectionPublications,
+	SectionAwards,
+	SectionVolunteer,
+	SectionCustom,
+	SectionSkills,
+	SectionLanguages,
+}
+
+// SectionLayout configures one section of the rendered CV
+type SectionLayout struct {
+	Section string `json:"section"`
+	Hidden  bool   `json:"hidden,omitempty"`
+	Title   string `json:"title,omitempty"` // replaces the translated title; ignored for custom sections
+}
+
+// IsSection reports whether id is one of DefaultSectionOrder
+func IsSection(id string) bool {
+	for _, section := range DefaultSectionOrder {
+		if section == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Sections resolves CV.Layout into the list of sections every renderer
+// writes, in order. Sections listed in Layout come first, in the given
+// order; the others follow in DefaultSectionOrder so that new sections are
+// never dropped silently. Hidden and unknown sections are left out, as are
+// duplicates after their first occurrence.
+func (cv *CV) Sections() []SectionLayout {
+	seen := make(map[string]bool, len(DefaultSectionOrder))
+	var sections []SectionLayout
+
+	for _, layout := range cv.Layout {
+		if !IsSection(layout.Section) || seen[layout.Section] {
+			continue
+		}
+		seen[layout.Section] = true
+		if !layout.Hidden {
+			sections = append(sections, layout)
+		}
+	}
+	for _, id := range DefaultSectionOrder {
+		if !seen[id] {
+			sections = append(sections, SectionLayout{Section: id})
+		}
+	}
+	return sections
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestSections(t *testing.T) {
+	// defaultWithout returns DefaultSectionOrder without the given sections
+	defaultWithout := func(skip ...string) []string {
+		var ids []string
+		for _, id := range DefaultSectionOrder {
+			if !slices.Contains(skip, id) {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	}
+	tests := []struct {
+		name   string
+		layout []SectionLayout
+		want   []string
+		titles map[string]string
+	}{
+		{"no layout", nil, DefaultSectionOrder, nil},
+		{
+			"listed first",
+			[]SectionLayout{{Section: SectionSkills}, {Section: SectionExperience}},
+			append([]string{SectionSkills, SectionExperience}, defaultWithout(SectionSkills, SectionExperience)...),
+			nil,
+		},
+		{
+			"hidden",
+			[]SectionLayout{{Section: SectionSummary, Hidden: true}},
+			defaultWithout(SectionSummary),
+			nil,
+		},
+		{
+			"duplicate keeps its first occurrence",
+			[]SectionLayout{{Section: SectionEducation, Title: "Studies"}, {Section: SectionEducation, Hidden: true, Title: "School"}},
+			append([]string{SectionEducation}, defaultWithout(SectionEducation)...),
+			map[string]string{SectionEducation: "Studies"},
+		},
+		{
+			"hidden duplicate keeps the section hidden",
+			[]SectionLayout{{Section: SectionAwards, Hidden: true}, {Section: SectionAwards}},
+			defaultWithout(SectionAwards),
+			nil,
+		},
+		{
+			"unknown sections dropped",
+			[]SectionLayout{{Section: "hobbies"}, {Section: "Skills"}, {Section: SectionLanguages}},
+			append([]string{SectionLanguages}, defaultWithout(SectionLanguages)...),
+			nil,
+		},
+		{
+			"title kept",
+			[]SectionLayout{{Section: SectionExperience, Title: "Berufserfahrung"}},
+			append([]string{SectionExperience}, defaultWithout(SectionExperience)...),
+			map[string]string{SectionExperience: "Berufserfahrung"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cv := CV{Layout: tt.layout}
+			var got []string
+			for _, section := range cv.Sections() {
+				got = append(got, section.Section)
+				if section.Hidden {
+					t.Errorf("Sections() returned hidden section %s", section.Section)
+				}
+				if want := tt.titles[section.Section]; section.Title != want {
+					t.Errorf("Sections() %s title = %q, want %q", section.Section, section.Title, want)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sections() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
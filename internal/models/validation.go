@@ -0,0 +1,390 @@
+package models
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Validation limits applied by CV.Validate
+const (
+	MaxNameLength        = 100
+	MaxShortTextLength   = 200
+	MaxSummaryLength     = 2000
+	MaxDescriptionLength = 5000
+	MaxExperienceEntries = 50
+	MaxEducationEntries  = 20
+	MaxSkillEntries      = 100
+	MaxLanguageEntries   = 20
+	MaxProjectEntries    = 50
+	MaxOtherEntries      = 50 // certifications, awards, publications and volunteer work
+	MaxTechnologyEntries = 30
+	MaxHighlightEntries  = 20 // per experience or education entry
+	MaxCustomSections    = 10
+	MaxCustomEntries     = 50 // per custom section
+	MaxFitPages          = 10
+)
+
+// Violation codes reported in Violation.Code
+const (
+	CodeRequired       = "required"
+	CodeTooLong        = "too_long"
+	CodeTooMany        = "too_many"
+	CodeInvalidEmail   = "invalid_email"
+	CodeInvalidURL     = "invalid_url"
+	CodeInvalidPhone   = "invalid_phone"
+	CodeInvalidDate    = "invalid_date"
+	CodeInvalidCode    = "invalid_code"
+	CodeInvalidLevel   = "invalid_level"
+	CodeDateOrder      = "date_order"
+	CodeInvalidJSON    = "invalid_json"
+	CodeInvalidXML     = "invalid_xml"
+	CodeInvalidArchive = "invalid_archive"
+	CodeInvalidType    = "invalid_type"
+	CodeUnsupported    = "unsupported"
+	CodeConflict       = "conflict"
+	CodeOutOfRange     = "out_of_range"
+	CodeCannotFit      = "cannot_fit"
+)
+
+// Violation describes a problem with a single field of a CV. Field is a JSON
+// path such as "personalInfo.email" or "experience[2].endDate"; it is empty
+// when the problem concerns the document as a whole.
+type Violation struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors is the list of violations found in a CV
+type ValidationErrors []Violation
+
+func (v ValidationErrors) Error() string {
+	parts := make([]string, len(v))
+	for i, violation := range v {
+		if violation.Field == "" {
+			parts[i] = violation.Message
+			continue
+		}
+		parts[i] = violation.Field + ": " + violation.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+// dateLayouts are the accepted formats for start and end dates. The web form
+// sends YYYY-MM from <input type="month">.
+var dateLayouts = []string{"2006-01-02", "2006-01", "2006"}
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9 ().\-/]+$`)
+
+// languageCodePattern matches ISO 639-1 (two letters) and 639-2 (three
+// letters) codes
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// Validate checks the CV for missing required fields, malformed contact
+// details, inverted date ranges, oversized text and too many entries. It
+// returns nil when the CV is valid.
+func (cv *CV) Validate() ValidationErrors {
+	var v validator
+
+	p := cv.PersonalInfo
+	v.required("personalInfo.fullName", p.FullName)
+	v.maxLength("personalInfo.fullName", p.FullName, MaxNameLength)
+	v.required("personalInfo.email", p.Email)
+	v.email("personalInfo.email", p.Email)
+	v.phone("personalInfo.phone", p.Phone)
+	v.maxLength("personalInfo.location", p.Location, MaxShortTextLength)
+	v.url("personalInfo.linkedin", p.LinkedIn)
+	v.url("personalInfo.github", p.GitHub)
+	v.url("personalInfo.website", p.Website)
+	v.maxLength("personalInfo.summary", p.Summary, MaxSummaryLength)
+
+	v.maxEntries("experience", len(cv.Experience), MaxExperienceEntries)
+	for i, exp := range cv.Experience {
+		path := fmt.Sprintf("experience[%d]", i)
+		v.required(path+".company", exp.Company)
+		v.maxLength(path+".company", exp.Company, MaxShortTextLength)
+		v.required(path+".position", exp.Position)
+		v.maxLength(path+".position", exp.Position, MaxShortTextLength)
+		v.dateRange(path, exp.StartDate, exp.EndDate)
+		v.maxLength(path+".description", exp.Description, MaxDescriptionLength)
+		v.highlights(path, exp.Highlights)
+	}
+
+	v.maxEntries("education", len(cv.Education), MaxEducationEntries)
+	for i, edu := range cv.Education {
+		path := fmt.Sprintf("education[%d]", i)
+		v.required(path+".institution", edu.Institution)
+		v.maxLength(path+".institution", edu.Institution, MaxShortTextLength)
+		v.required(path+".degree", edu.Degree)
+		v.maxLength(path+".degree", edu.Degree, MaxShortTextLength)
+		v.dateRange(path, edu.StartDate, edu.EndDate)
+		v.maxLength(path+".description", edu.Description, MaxDescriptionLength)
+		v.highlights(path, edu.Highlights)
+	}
+
+	v.maxEntries("skills", len(cv.Skills), MaxSkillEntries)
+	for i, skill := range cv.Skills {
+		path := fmt.Sprintf("skills[%d]", i)
+		v.required(path+".name", skill.Name)
+		v.maxLength(path+".name", skill.Name, MaxNameLength)
+		v.maxLength(path+".level", skill.Level, MaxNameLength)
+	}
+
+	v.maxEntries("languages", len(cv.Languages), MaxLanguageEntries)
+	for i, language := range cv.Languages {
+		path := fmt.Sprintf("languages[%d]", i)
+		v.required(path+".name", language.Name)
+		v.maxLength(path+".name", language.Name, MaxNameLength)
+		v.languageCode(path+".code", language.Code)
+		v.languageLevel(path+".level", language.Level)
+		v.maxLength(path+".certificate", language.Certificate, MaxNameLength)
+	}
+
+	v.maxEntries("projects", len(cv.Projects), MaxProjectEntries)
+	for i, project := range cv.Projects {
+		path := fmt.Sprintf("projects[%d]", i)
+		v.required(path+".name", project.Name)
+		v.maxLength(path+".name", project.Name, MaxShortTextLength)
+		v.maxLength(path+".role", project.Role, MaxShortTextLength)
+		v.url(path+".url", project.URL)
+		v.maxEntries(path+".technologies", len(project.Technologies), MaxTechnologyEntries)
+		for j, tech := range project.Technologies {
+			v.maxLength(fmt.Sprintf("%s.technologies[%d]", path, j), tech, MaxNameLength)
+		}
+		v.dateRange(path, project.StartDate, project.EndDate)
+		v.maxLength(path+".description", project.Description, MaxDescriptionLength)
+	}
+
+	v.maxEntries("certifications", len(cv.Certifications), MaxOtherEntries)
+	for i, cert := range cv.Certifications {
+		path := fmt.Sprintf("certifications[%d]", i)
+		v.required(path+".name", cert.Name)
+		v.maxLength(path+".name", cert.Name, MaxShortTextLength)
+		v.maxLength(path+".issuer", cert.Issuer, MaxShortTextLength)
+		v.maxLength(path+".credentialId", cert.CredentialID, MaxNameLength)
+		v.url(path+".credentialUrl", cert.CredentialURL)
+		dateOK := v.date(path+".date", strings.TrimSpace(cert.Date))
+		if v.date(path+".expiryDate", strings.TrimSpace(cert.ExpiryDate)) && dateOK {
+			v.dateOrder(path+".expiryDate", cert.Date, cert.ExpiryDate, "must not be before the issue date")
+		}
+	}
+
+	v.maxEntries("awards", len(cv.Awards), MaxOtherEntries)
+	for i, award := range cv.Awards {
+		path := fmt.Sprintf("awards[%d]", i)
+		v.required(path+".title", award.Title)
+		v.maxLength(path+".title", award.Title, MaxShortTextLength)
+		v.maxLength(path+".issuer", award.Issuer, MaxShortTextLength)
+		v.date(path+".date", strings.TrimSpace(award.Date))
+		v.maxLength(path+".description", award.Description, MaxDescriptionLength)
+	}
+
+	v.maxEntries("publications", len(cv.Publications), MaxOtherEntries)
+	for i, publication := range cv.Publications {
+		path := fmt.Sprintf("publications[%d]", i)
+		v.required(path+".title", publication.Title)
+		v.maxLength(path+".title", publication.Title, MaxShortTextLength)
+		v.maxLength(path+".publisher", publication.Publisher, MaxShortTextLength)
+		v.date(path+".date", strings.TrimSpace(publication.Date))
+		v.url(path+".url", publication.URL)
+		v.maxLength(path+".description", publication.Description, MaxDescriptionLength)
+	}
+
+	v.maxEntries("volunteer", len(cv.Volunteer), MaxOtherEntries)
+	for i, volunteer := range cv.Volunteer {
+		path := fmt.Sprintf("volunteer[%d]", i)
+		v.required(path+".organization", volunteer.Organization)
+		v.maxLength(path+".organization", volunteer.Organization, MaxShortTextLength)
+		v.maxLength(path+".role", volunteer.Role, MaxShortTextLength)
+		v.dateRange(path, volunteer.StartDate, volunteer.EndDate)
+		v.maxLength(path+".description", volunteer.Description, MaxDescriptionLength)
+	}
+
+	v.maxEntries("customSections", len(cv.CustomSections), MaxCustomSections)
+	for i, section := range cv.CustomSections {
+		path := fmt.Sprintf("customSections[%d]", i)
+		v.required(path+".title", section.Title)
+		v.maxLength(path+".title", section.Title, MaxNameLength)
+		v.maxLength(path+".content", section.Content, MaxDescriptionLength)
+		hasContent := strings.TrimSpace(section.Content) != ""
+		switch {
+		case hasContent && len(section.Entries) > 0:
+			v.add(path+".entries", CodeConflict, "must be empty when content is set")
+		case !hasContent && len(section.Entries) == 0:
+			v.add(path+".content", CodeRequired, "content or entries are required")
+		}
+
+		v.maxEntries(path+".entries", len(section.Entries), MaxCustomEntries)
+		for j, e := range section.Entries {
+			entryPath := fmt.Sprintf("%s.entries[%d]", path, j)
+			v.required(entryPath+".title", e.Title)
+			v.maxLength(entryPath+".title", e.Title, MaxShortTextLength)
+			v.maxLength(entryPath+".subtitle", e.Subtitle, MaxShortTextLength)
+			v.dateRange(entryPath, e.StartDate, e.EndDate)
+			v.maxLength(entryPath+".description", e.Description, MaxDescriptionLength)
+		}
+	}
+
+	seen := make(map[string]bool, len(cv.Layout))
+	for i, layout := range cv.Layout {
+		path := fmt.Sprintf("layout[%d]", i)
+		switch {
+		case !IsSection(layout.Section):
+			v.add(path+".section", CodeUnsupported, "must be one of "+strings.Join(DefaultSectionOrder, ", "))
+		case seen[layout.Section]:
+			v.add(path+".section", CodeConflict, "is listed more than once")
+		}
+		seen[layout.Section] = true
+		v.maxLength(path+".title", layout.Title, MaxNameLength)
+	}
+
+	if cv.FitPages < 0 || cv.FitPages > MaxFitPages {
+		v.add("fitPages", CodeOutOfRange, fmt.Sprintf("must be between 0 and %d", MaxFitPages))
+	}
+
+	return v.errs
+}
+
+// validator accumulates violations while a CV is checked
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) add(field, code, message string) {
+	v.errs = append(v.errs, Violation{Field: field, Code: code, Message: message})
+}
+
+func (v *validator) required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, CodeRequired, "is required")
+	}
+}
+
+func (v *validator) maxLength(field, value string, limit int) {
+	if utf8.RuneCountInString(value) > limit {
+		v.add(field, CodeTooLong, fmt.Sprintf("must be at most %d characters", limit))
+	}
+}
+
+func (v *validator) maxEntries(field string, count, limit int) {
+	if count > limit {
+		v.add(field, CodeTooMany, fmt.Sprintf("must contain at most %d entries", limit))
+	}
+}
+
+// highlights checks the bullet points of an experience or education entry
+func (v *validator) highlights(path string, highlights []string) {
+	v.maxEntries(path+".highlights", len(highlights), MaxHighlightEntries)
+	for i, highlight := range highlights {
+		field := fmt.Sprintf("%s.highlights[%d]", path, i)
+		v.required(field, highlight)
+		v.maxLength(field, highlight, MaxSummaryLength)
+	}
+}
+
+func (v *validator) email(field, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Address != value {
+		v.add(field, CodeInvalidEmail, "must be a valid email address")
+	}
+}
+
+func (v *validator) phone(field, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	digits := 0
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	if !phonePattern.MatchString(value) || digits < 6 || digits > 15 {
+		v.add(field, CodeInvalidPhone, "must be a valid phone number")
+	}
+}
+
+func (v *validator) languageCode(field, value string) {
+	if value != "" && !languageCodePattern.MatchString(value) {
+		v.add(field, CodeInvalidCode, "must be a lowercase ISO 639 language code such as \"en\"")
+	}
+}
+
+func (v *validator) languageLevel(field, value string) {
+	if value != "" && LevelRank(value) == 0 {
+		v.add(field, CodeInvalidLevel, "must be one of "+strings.Join(LanguageLevels, ", "))
+	}
+}
+
+// url accepts absolute http(s) URLs as well as bare hosts such as
+// "github.com/user", which are common in hand-written CVs
+func (v *validator) url(field, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	if utf8.RuneCountInString(value) > MaxShortTextLength {
+		v.add(field, CodeTooLong, fmt.Sprintf("must be at most %d characters", MaxShortTextLength))
+		return
+	}
+	if !strings.Contains(value, "://") {
+		value = "https://" + value
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || !strings.Contains(u.Host, ".") {
+		v.add(field, CodeInvalidURL, "must be a valid http(s) URL")
+	}
+}
+
+// dateRange checks the start and end dates of an entry. An empty end date
+// means the entry is ongoing. Dates of different precision are compared at
+// the coarser one, so "2020" to "2020-03" is accepted.
+func (v *validator) dateRange(path, start, end string) {
+	start, end = strings.TrimSpace(start), strings.TrimSpace(end)
+	startOK := v.date(path+".startDate", start)
+	endOK := v.date(path+".endDate", end)
+	if !startOK || !endOK {
+		return
+	}
+	v.dateOrder(path+".endDate", start, end, "must not be before the start date")
+}
+
+// dateOrder reports field when the valid dates first and last are inverted
+func (v *validator) dateOrder(field, first, last, message string) {
+	first, last = strings.TrimSpace(first), strings.TrimSpace(last)
+	n := min(len(first), len(last))
+	if last[:n] < first[:n] {
+		v.add(field, CodeDateOrder, message)
+	}
+}
+
+func (v *validator) date(field, value string) bool {
+	if value == "" {
+		return false
+	}
+	if _, ok := ParseDate(value); ok {
+		return true
+	}
+	v.add(field, CodeInvalidDate, "must be formatted as YYYY, YYYY-MM or YYYY-MM-DD")
+	return false
+}
+
+// ParseDate parses a CV date in one of the accepted layouts
+func ParseDate(value string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+// validCV returns a CV that passes Validate, for the tests to break
+func validCV() CV {
+	return CV{
+		PersonalInfo: PersonalInfo{FullName: "Zofia Nowak", Email: "zofia@example.com"},
+		Experience: []Experience{
+			{Company: "Acme", Position: "Engineer", StartDate: "2020-01", EndDate: "2022-06"},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cv *CV)
+		field  string
+		code   string
+	}{
+		{"valid", func(cv *CV) {}, "", ""},
+		{"missing name", func(cv *CV) { cv.PersonalInfo.FullName = " " }, "personalInfo.fullName", CodeRequired},
+		{"name too long", func(cv *CV) { cv.PersonalInfo.FullName = strings.Repeat("a", MaxNameLength+1) }, "personalInfo.fullName", CodeTooLong},
+		{"missing email", func(cv *CV) { cv.PersonalInfo.Email = "" }, "personalInfo.email", CodeRequired},
+		{"phone", func(cv *CV) { cv.PersonalInfo.Phone = "call me" }, "personalInfo.phone", CodeInvalidPhone},
+		{"missing company", func(cv *CV) { cv.Experience[0].Company = "" }, "experience[0].company", CodeRequired},
+		{"end before start", func(cv *CV) { cv.Experience[0].EndDate = "2019-12" }, "experience[0].endDate", CodeDateOrder},
+		{"bad start date", func(cv *CV) { cv.Experience[0].StartDate = "01/2020" }, "experience[0].startDate", CodeInvalidDate},
+		{"too many skills", func(cv *CV) { cv.Skills = make([]Skill, MaxSkillEntries+1) }, "skills", CodeTooMany},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cv := validCV()
+			tt.modify(&cv)
+			errs := cv.Validate()
+			if tt.code == "" {
+				if errs != nil {
+					t.Fatalf("Validate() = %v, want nil", errs)
+				}
+				return
+			}
+			for _, v := range errs {
+				if v.Field == tt.field && v.Code == tt.code {
+					return
+				}
+			}
+			t.Errorf("Validate() = %v, want %s on %s", errs, tt.code, tt.field)
+		})
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		value string
+		valid bool
+	}{
+		{"", true},
+		{"https://zofia.dev", true},
+		{"http://example.com/path?q=1", true},
+		{"zofia.dev", true},
+		{"github.com/zofia", true},
+		{"linkedin.com/in/zofia", true},
+		{"ftp://example.com", false},
+		{"javascript:alert(1)", false},
+		{"localhost", false},
+		{"https://", false},
+		{"not a url", false},
+		{"https://example.com/" + strings.Repeat("a", MaxShortTextLength), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			var v validator
+			v.url("website", tt.value)
+			if got := len(v.errs) == 0; got != tt.valid {
+				t.Errorf("url(%q) valid = %v, want %v (%v)", tt.value, got, tt.valid, v.errs)
+			}
+		})
+	}
+}
+
+func TestValidateEmail(t *testing.T) {
+	tests := []struct {
+		value string
+		valid bool
+	}{
+		{"zofia@example.com", true},
+		{" zofia@example.com ", true},
+		{"zofia.nowak+cv@mail.example.org", true},
+		{"zofia", false},
+		{"zofia@", false},
+		{"Zofia <zofia@example.com>", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			var v validator
+			v.email("email", tt.value)
+			if got := len(v.errs) == 0; got != tt.valid {
+				t.Errorf("email(%q) valid = %v, want %v", tt.value, got, tt.valid)
+			}
+		})
+	}
+}
+
+func TestValidateDateRange(t *testing.T) {
+	tests := []struct {
+		start, end string
+		code       string
+	}{
+		{"2020", "", ""},
+		{"2020-03", "2021", ""},
+		{"2020-03-15", "2020-03", ""},
+		{"2020", "2020-03", ""},
+		{"2021", "2020-12", CodeDateOrder},
+		{"2020-05-02", "2020-05-01", CodeDateOrder},
+		{"2020-13", "", CodeInvalidDate},
+		{"2020", "soon", CodeInvalidDate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.start+"_"+tt.end, func(t *testing.T) {
+			var v validator
+			v.dateRange("experience[0]", tt.start, tt.end)
+			switch {
+			case tt.code == "" && len(v.errs) > 0:
+				t.Errorf("dateRange(%q, %q) = %v, want no violations", tt.start, tt.end, v.errs)
+			case tt.code != "" && (len(v.errs) != 1 || v.errs[0].Code != tt.code):
+				t.Errorf("dateRange(%q, %q) = %v, want one %s", tt.start, tt.end, v.errs, tt.code)
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{"2020", "2020-01-01", true},
+		{"2020-07", "2020-07-01", true},
+		{"2020-07-15", "2020-07-15", true},
+		{"2020-02-30", "", false},
+		{"July 2020", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := ParseDate(tt.value)
+			if ok != tt.ok || (ok && got.Format("2006-01-02") != tt.want) {
+				t.Errorf("ParseDate(%q) = %v, %v, want %s, %v", tt.value, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,425 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"cv-generator/internal/models"
+)
+
+// The renderers share the text of a CV: the same entries, labels and
+// translations, laid out in their own way.
+
+// cleanText normalizes user input before it is rendered. Text is written
+// as UTF-8, so only invalid byte sequences and null bytes need to be
+// removed.
+func cleanText(text string) string {
+	result := strings.ToValidUTF8(text, "")
+	result = strings.ReplaceAll(result, "\x00", "")
+	result = strings.TrimSpace(result)
+
+	return result
+}
+
+// oneLine collapses every run of white space, line breaks included, into a
+// single space
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// docMetadata is the document properties of a generated CV
+type docMetadata struct {
+	title    string
+	author   string
+	subject  string
+	keywords []string
+}
+
+// metadata returns the document properties that viewers and applicant
+// tracking systems read
+func metadata(cv models.CV) docMetadata {
+	name := cleanText(cv.PersonalInfo.FullName)
+	meta := docMetadata{
+		title:   joinNonEmpty(" - ", name, translate("CV", cv.Language)),
+		author:  name,
+		subject: translate("CV", cv.Language),
+	}
+
+	// The first experience entry is the current or latest position
+	if len(cv.Experience) > 0 {
+		meta.subject = cleanText(cv.Experience[0].Position)
+	}
+	for _, skill := range cv.Skills {
+		if name := cleanText(skill.Name); name != "" {
+			meta.keywords = append(meta.keywords, name)
+		}
+	}
+	return meta
+}
+
+// contactParts lists the non-empty contact details in display order, each
+// linked to its mailto:, tel: or web address
+func contactParts(info models.PersonalInfo) []mdSpan {
+	var contactParts []mdSpan
+	if email := cleanText(info.Email); email != "" {
+		contactParts = append(contactParts, mdSpan{text: email, link: "mailto:" + email})
+	}
+	if phone := cleanText(info.Phone); phone != "" {
+		contactParts = append(contactParts, mdSpan{text: phone, link: telLink(phone)})
+	}
+	if info.Location != "" {
+		contactParts = append(contactParts, mdSpan{text: cleanText(info.Location)})
+	}
+	if linkedIn := cleanText(info.LinkedIn); linkedIn != "" {
+		contactParts = append(contactParts, mdSpan{text: "LinkedIn: " + linkedIn, link: webLink(linkedIn)})
+	}
+	if gitHub := cleanText(info.GitHub); gitHub != "" {
+		contactParts = append(contactParts, mdSpan{text: "GitHub: " + gitHub, link: webLink(gitHub)})
+	}
+	if website := cleanText(info.Website); website != "" {
+		contactParts = append(contactParts, mdSpan{text: website, link: webLink(website)})
+	}
+	return contactParts
+}
+
+// webLink returns the address to link a URL field to. The validator accepts
+// bare hosts such as "github.com/user", which are taken as https URLs; the
+// result then goes through safeLink like any other link.
+func webLink(value string) string {
+	value = cleanText(value)
+	if value != "" && !strings.Contains(value, "://") {
+		value = "https://" + value
+	}
+	return safeLink(value)
+}
+
+// urlDetail is a detail line showing a URL field as typed, linked to its
+// address
+func urlDetail(value string) mdSpan {
+	return mdSpan{text: value, link: webLink(value)}
+}
+
+// telLink returns a tel: URI for a phone number as typed, keeping only the
+// leading plus sign and the digits
+func telLink(phone string) string {
+	var b strings.Builder
+	for i, r := range phone {
+		if (r >= '0' && r <= '9') || (r == '+' && i == 0) {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "tel:" + b.String()
+}
+
+// entry is one item of a list section such as experience or projects
+type entry struct {
+	title       string
+	subtitle    string   // dates, issuer and similar, in light italics
+	details     []mdSpan // extra lines such as a link or tech stack
+	description string   // Markdown, see parseMarkdown
+	highlights  []string // bullet points after the description
+
+	// The parts of title and subtitle, for layouts with a slot for each
+	// such as moderncv's \cventry
+	dates        string // date or date range
+	role         string // position, degree or name of the item
+	organization string // company, institution, issuer or similar
+}
+
+// blocks parses the description and appends the highlights as bullets
+func (e entry) blocks() []mdBlock {
+	blocks := parseMarkdown(cleanText(e.description))
+	for _, highlight := range e.highlights {
+		if highlight = cleanText(highlight); highlight != "" {
+			blocks = append(blocks, mdBlock{bullet: true, spans: parseInline(highlight)})
+		}
+	}
+	return blocks
+}
+
+// dateRange formats the dates of an entry; an empty end date means the
+// entry is ongoing
+func dateRange(startDate, endDate, lang string) string {
+	startDate, endDate = strings.TrimSpace(startDate), strings.TrimSpace(endDate)
+	if startDate == "" && endDate == "" {
+		return ""
+	}
+	if endDate == "" {
+		endDate = translate("Present", lang)
+	}
+	return startDate + " - " + endDate
+}
+
+// lines returns the non-empty subtitle and detail lines; details that are
+// web addresses keep their link
+func (e entry) lines() []mdSpan {
+	var lines []mdSpan
+	for _, line := range append([]mdSpan{{text: e.subtitle}}, e.details...) {
+		if line.text = cleanText(line.text); line.text != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// sectionEntries returns the entries of a built-in list section, and false
+// for the sections that are not made of entries
+func sectionEntries(cv models.CV, section string) ([]entry, bool) {
+	lang := cv.Language
+	switch section {
+	case models.SectionExperience:
+		return experienceEntries(cv.Experience, lang), true
+	case models.SectionProjects:
+		return projectEntries(cv.Projects, lang), true
+	case models.SectionEducation:
+		return educationEntries(cv.Education, lang), true
+	case models.SectionCertifications:
+		return certificationEntries(cv.Certifications, lang), true
+	case models.SectionPublications:
+		return publicationEntries(cv.Publications), true
+	case models.SectionAwards:
+		return awardEntries(cv.Awards), true
+	case models.SectionVolunteer:
+		return volunteerEntries(cv.Volunteer, lang), true
+	}
+	return nil, false
+}
+
+func experienceEntries(experiences []models.Experience, lang string) []entry {
+	var entries []entry
+	for _, exp := range experiences {
+		atWord := translate("at", lang)
+		dates := dateRange(exp.StartDate, exp.EndDate, lang)
+		entries = append(entries, entry{
+			title:        fmt.Sprintf("%s %s %s", exp.Position, atWord, exp.Company),
+			subtitle:     dates,
+			description:  exp.Description,
+			highlights:   exp.Highlights,
+			dates:        dates,
+			role:         exp.Position,
+			organization: exp.Company,
+		})
+	}
+	return entries
+}
+
+func educationEntries(education []models.Education, lang string) []entry {
+	var entries []entry
+	for _, edu := range education {
+		dates := dateRange(edu.StartDate, edu.EndDate, lang)
+		entries = append(entries, entry{
+			title:        fmt.Sprintf("%s - %s", edu.Degree, edu.Institution),
+			subtitle:     dates,
+			description:  edu.Description,
+			highlights:   edu.Highlights,
+			dates:        dates,
+			role:         edu.Degree,
+			organization: edu.Institution,
+		})
+	}
+	return entries
+}
+
+func projectEntries(projects []models.Project, lang string) []entry {
+	var entries []entry
+	for _, project := range projects {
+		dates := dateRange(project.StartDate, project.EndDate, lang)
+		e := entry{
+			title:        joinNonEmpty(" - ", project.Name, project.Role),
+			subtitle:     dates,
+			description:  project.Description,
+			dates:        dates,
+			role:         project.Name,
+			organization: project.Role,
+		}
+		if len(project.Technologies) > 0 {
+			e.details = append(e.details, mdSpan{text: translate("Technologies", lang) + ": " + strings.Join(project.Technologies, ", ")})
+		}
+		e.details = append(e.details, urlDetail(project.URL))
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func certificationEntries(certifications []models.Certification, lang string) []entry {
+	var entries []entry
+	for _, cert := range certifications {
+		var expiry string
+		if cert.ExpiryDate != "" {
+			expiry = translate("Expires", lang) + " " + cert.ExpiryDate
+		}
+		var credential string
+		if cert.CredentialID != "" {
+			credential = translate("Credential ID", lang) + ": " + cert.CredentialID
+		}
+		entries = append(entries, entry{
+			title:        cert.Name,
+			subtitle:     joinNonEmpty(" • ", cert.Issuer, cert.Date, expiry),
+			details:      []mdSpan{{text: credential}, urlDetail(cert.CredentialURL)},
+			dates:        joinNonEmpty(", ", cert.Date, expiry),
+			role:         cert.Name,
+			organization: cert.Issuer,
+		})
+	}
+	return entries
+}
+
+func awardEntries(awards []models.Award) []entry {
+	var entries []entry
+	for _, award := range awards {
+		entries = append(entries, entry{
+			title:        award.Title,
+			subtitle:     joinNonEmpty(" • ", award.Issuer, award.Date),
+			description:  award.Description,
+			dates:        award.Date,
+			role:         award.Title,
+			organization: award.Issuer,
+		})
+	}
+	return entries
+}
+
+func publicationEntries(publications []models.Publication) []entry {
+	var entries []entry
+	for _, publication := range publications {
+		entries = append(entries, entry{
+			title:        publication.Title,
+			subtitle:     joinNonEmpty(" • ", publication.Publisher, publication.Date),
+			details:      []mdSpan{urlDetail(publication.URL)},
+			description:  publication.Description,
+			dates:        publication.Date,
+			role:         publication.Title,
+			organization: publication.Publisher,
+		})
+	}
+	return entries
+}
+
+func volunteerEntries(volunteer []models.Volunteer, lang string) []entry {
+	var entries []entry
+	for _, v := range volunteer {
+		dates := dateRange(v.StartDate, v.EndDate, lang)
+		e := entry{
+			title:       v.Organization,
+			subtitle:    dates,
+			description: v.Description,
+			dates:       dates,
+			role:        v.Organization,
+		}
+		if v.Role != "" {
+			e.title = fmt.Sprintf("%s %s %s", v.Role, translate("at", lang), v.Organization)
+			e.role, e.organization = v.Role, v.Organization
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// customEntries returns the entries of a user-defined section. A single
+// date is shown as is, a start and end date as a range.
+func customEntries(section models.CustomSection, lang string) []entry {
+	var entries []entry
+	for _, e := range section.Entries {
+		dates := cleanText(e.StartDate)
+		if e.EndDate != "" {
+			dates = dateRange(e.StartDate, e.EndDate, lang)
+		}
+		entries = append(entries, entry{
+			title:        e.Title,
+			subtitle:     joinNonEmpty(" • ", e.Subtitle, dates),
+			description:  e.Description,
+			dates:        dates,
+			role:         e.Title,
+			organization: e.Subtitle,
+		})
+	}
+	return entries
+}
+
+// skillLabel returns a skill with its translated level, if any
+func skillLabel(skill models.Skill, lang string) string {
+	label := cleanText(skill.Name)
+	if skill.Level != "" {
+		label += " (" + translate(cleanText(skill.Level), lang) + ")"
+	}
+	return label
+}
+
+// languageLabel returns a language with its level label and certificate
+func languageLabel(language models.Language, lang string) string {
+	label := cleanText(language.Name)
+	if language.Level != "" {
+		label += " — " + languageLevelLabel(language.Level, lang)
+	}
+	if certificate := cleanText(language.Certificate); certificate != "" {
+		label += ", " + certificate
+	}
+	return label
+}
+
+// flowWriter is implemented by the renderers that write a CV as a single
+// column of headings, entries and lists rather than laying out pages
+type flowWriter interface {
+	writeHeader(name string, contact []mdSpan)
+	writeSection(title string)
+	writeEntries(entries []entry)
+	writeText(text string) // a plain paragraph, on one line
+	writeList(items []string)
+}
+
+// writeFlow writes cv to w in the same section order and with the same
+// titles as the PDF. Empty sections are left out.
+func writeFlow(w flowWriter, cv models.CV) {
+	lang := cv.Language
+	w.writeHeader(cleanText(cv.PersonalInfo.FullName), contactParts(cv.PersonalInfo))
+
+	for _, layout := range cv.Sections() {
+		title := sectionTitle(layout, lang)
+
+		if entries, ok := sectionEntries(cv, layout.Section); ok {
+			if len(entries) > 0 {
+				w.writeSection(title)
+				w.writeEntries(entries)
+			}
+			continue
+		}
+
+		switch layout.Section {
+		case models.SectionSummary:
+			if summary := cleanText(cv.PersonalInfo.Summary); summary != "" {
+				w.writeSection(title)
+				w.writeText(oneLine(summary))
+			}
+		case models.SectionCustom:
+			for _, section := range cv.CustomSections {
+				w.writeSection(cleanText(section.Title))
+				if len(section.Entries) == 0 {
+					w.writeText(oneLine(section.Content))
+					continue
+				}
+				w.writeEntries(customEntries(section, lang))
+			}
+		case models.SectionSkills:
+			if len(cv.Skills) > 0 {
+				var items []string
+				for _, skill := range cv.Skills {
+					items = append(items, skillLabel(skill, lang))
+				}
+				w.writeSection(title)
+				w.writeList(items)
+			}
+		case models.SectionLanguages:
+			if len(cv.Languages) > 0 {
+				var items []string
+				for _, language := range cv.Languages {
+					items = append(items, languageLabel(language, lang))
+				}
+				w.writeSection(title)
+				w.writeList(items)
+			}
+		}
+	}
+}
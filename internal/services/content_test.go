@@ -0,0 +1,147 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"cv-generator/internal/models"
+)
+
+func TestWebLink(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"https://zofia.dev", "https://zofia.dev"},
+		{"http://zofia.dev/cv", "http://zofia.dev/cv"},
+		{"zofia.dev", "https://zofia.dev"},
+		{" linkedin.com/in/zofia ", "https://linkedin.com/in/zofia"},
+		{"github.com/zofia", "https://github.com/zofia"},
+		{"javascript:alert(1)", ""},
+		{"ftp://zofia.dev", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := webLink(tt.input); got != tt.want {
+				t.Errorf("webLink(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntryBuilders(t *testing.T) {
+	type view struct {
+		title        string
+		lines        []mdSpan
+		description  string
+		dates        string
+		role         string
+		organization string
+	}
+	tests := []struct {
+		name    string
+		entries []entry
+		want    view
+	}{
+		{
+			"project with every field",
+			projectEntries([]models.Project{{
+				Name: "cvgen", Role: "Author", URL: "github.com/zofia/cvgen", Technologies: []string{"Go", "SQLite"},
+				StartDate: "2023-01", Description: "CV generator",
+			}}, "en"),
+			view{
+				title: "cvgen - Author",
+				lines: []mdSpan{
+					{text: "2023-01 - Present"},
+					{text: "Technologies: Go, SQLite"},
+					{text: "github.com/zofia/cvgen", link: "https://github.com/zofia/cvgen"},
+				},
+				description: "CV generator", dates: "2023-01 - Present", role: "cvgen", organization: "Author",
+			},
+		},
+		{
+			"project with a name only",
+			projectEntries([]models.Project{{Name: "cvgen"}}, "en"),
+			view{title: "cvgen", role: "cvgen"},
+		},
+		{
+			"project with an unsafe URL",
+			projectEntries([]models.Project{{Name: "cvgen", URL: "javascript:alert(1)"}}, "en"),
+			view{title: "cvgen", lines: []mdSpan{{text: "javascript:alert(1)"}}, role: "cvgen"},
+		},
+		{
+			"certification with every field",
+			certificationEntries([]models.Certification{{
+				Name: "CKA", Issuer: "CNCF", Date: "2021-05", ExpiryDate: "2024-05",
+				CredentialID: "ABC-123", CredentialURL: "https://cncf.io/c/1",
+			}}, "en"),
+			view{
+				title: "CKA",
+				lines: []mdSpan{
+					{text: "CNCF • 2021-05 • Expires 2024-05"},
+					{text: "Credential ID: ABC-123"},
+					{text: "https://cncf.io/c/1", link: "https://cncf.io/c/1"},
+				},
+				dates: "2021-05, Expires 2024-05", role: "CKA", organization: "CNCF",
+			},
+		},
+		{
+			"certification without credential",
+			certificationEntries([]models.Certification{{Name: "CKA", Date: "2021"}}, "en"),
+			view{title: "CKA", lines: []mdSpan{{text: "2021"}}, dates: "2021", role: "CKA"},
+		},
+		{
+			"award",
+			awardEntries([]models.Award{{Title: "Best talk", Issuer: "GopherCon", Date: "2022", Description: "Voted by attendees"}}),
+			view{
+				title: "Best talk", lines: []mdSpan{{text: "GopherCon • 2022"}},
+				description: "Voted by attendees", dates: "2022", role: "Best talk", organization: "GopherCon",
+			},
+		},
+		{
+			"award without issuer or date",
+			awardEntries([]models.Award{{Title: "Best talk"}}),
+			view{title: "Best talk", role: "Best talk"},
+		},
+		{
+			"publication",
+			publicationEntries([]models.Publication{{Title: "Go at scale", Publisher: "ACM", Date: "2021", URL: "doi.org/10.1/x"}}),
+			view{
+				title: "Go at scale",
+				lines: []mdSpan{{text: "ACM • 2021"}, {text: "doi.org/10.1/x", link: "https://doi.org/10.1/x"}},
+				dates: "2021", role: "Go at scale", organization: "ACM",
+			},
+		},
+		{
+			"publication with a title only",
+			publicationEntries([]models.Publication{{Title: "Go at scale"}}),
+			view{title: "Go at scale", role: "Go at scale"},
+		},
+		{
+			"volunteer with a role",
+			volunteerEntries([]models.Volunteer{{Organization: "Red Cross", Role: "Driver", StartDate: "2019", EndDate: "2020"}}, "en"),
+			view{
+				title: "Driver at Red Cross", lines: []mdSpan{{text: "2019 - 2020"}},
+				dates: "2019 - 2020", role: "Driver", organization: "Red Cross",
+			},
+		},
+		{
+			"volunteer without a role or dates",
+			volunteerEntries([]models.Volunteer{{Organization: "Red Cross", Description: "Weekends"}}, "en"),
+			view{title: "Red Cross", description: "Weekends", role: "Red Cross"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.entries) != 1 {
+				t.Fatalf("got %d entries, want 1", len(tt.entries))
+			}
+			e := tt.entries[0]
+			got := view{e.title, e.lines(), e.description, e.dates, e.role, e.organization}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("entry = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"cv-generator/internal/models"
+)
+
+// DOCXService renders a CV as a Word document: an Office Open XML package
+// written with the standard library. The text flows in one column with real
+// heading, list and hyperlink styles, so the document stays editable in
+// Word and LibreOffice.
+type DOCXService struct{}
+
+// DOCXContentType is the media type of the generated documents
+const DOCXContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+func NewDOCXService() *DOCXService {
+	return &DOCXService{}
+}
+
+// Office Open XML namespaces and relationship types
+const (
+	docxNSMain     = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+	docxNSRel      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	docxNSPkgRel   = "http://schemas.openxmlformats.org/package/2006/relationships"
+	docxRelStyles  = docxNSRel + "/styles"
+	docxRelNumber  = docxNSRel + "/numbering"
+	docxRelLink    = docxNSRel + "/hyperlink"
+	docxRelDoc     = docxNSRel + "/officeDocument"
+	docxRelCore    = "http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties"
+	docxRelApp     = docxNSRel + "/extended-properties"
+	docxFontFamily = "Calibri"
+)
+
+// docxPageSizes are the page sizes in twentieths of a point, by gofpdf name
+var docxPageSizes = map[string][2]int{
+	"A3":     {16838, 23811},
+	"A4":     {11906, 16838},
+	"A5":     {8391, 11906},
+	"Letter": {12240, 15840},
+	"Legal":  {12240, 20160},
+}
+
+// docxLanguages are the proofing languages of the supported CV languages
+var docxLanguages = map[string]string{
+	"en": "en-US",
+	"es": "es-ES",
+}
+
+// GenerateCV renders cv as a DOCX document. The theme provides the colors,
+// margins and page size; ctx only carries logging attributes such as the
+// request ID.
+func (s *DOCXService) GenerateCV(ctx context.Context, cv models.CV) ([]byte, error) {
+	theme, ok := GetTheme(cv.Theme)
+	if !ok {
+		return nil, fmt.Errorf("unknown theme %q", cv.Theme)
+	}
+	pageSize := theme.PageSize
+	if cv.PageSize != "" {
+		pageSize = cv.PageSize
+	}
+	size, ok := docxPageSizes[pageSize]
+	if !ok {
+		return nil, fmt.Errorf("unsupported page size %q", pageSize)
+	}
+	slog.DebugContext(ctx, "generating DOCX", "language", cv.Language, "theme", theme.Name)
+
+	doc := &docxDocument{links: map[string]string{}}
+	writeFlow(doc, cv)
+
+	buffer := &bytes.Buffer{}
+	zw := zip.NewWriter(buffer)
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxPackageRels},
+		{"docProps/core.xml", docxCoreProperties(metadata(cv))},
+		{"docProps/app.xml", docxAppProperties},
+		{"word/document.xml", doc.xml(size, theme.Spacing.Margin)},
+		{"word/styles.xml", docxStyles(theme, cv.Language)},
+		{"word/numbering.xml", docxNumbering},
+		{"word/_rels/document.xml.rels", doc.rels()},
+	}
+	for _, file := range files {
+		w, err := zw.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write([]byte(file.content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	slog.DebugContext(ctx, "DOCX generated", "bytes", buffer.Len())
+	return buffer.Bytes(), nil
+}
+
+// docxDocument accumulates the body of word/document.xml and the hyperlink
+// relationships it refers to
+type docxDocument struct {
+	body  strings.Builder
+	links map[string]string // target URL to relationship ID
+	order []string          // targets in relationship ID order
+}
+
+// writeHeader writes the name as the document title and the contact
+// details on one line
+func (d *docxDocument) writeHeader(name string, contact []mdSpan) {
+	d.paragraph("Title", []mdSpan{{text: name}})
+	if len(contact) > 0 {
+		var spans []mdSpan
+		for i, part := range contact {
+			if i > 0 {
+				spans = append(spans, mdSpan{text: " • "})
+			}
+			spans = append(spans, part)
+		}
+		d.paragraph("Contact", spans)
+	}
+}
+
+// writeSection writes a section title in the Heading 1 style, as given, so
+// custom titles keep the case they were typed in
+func (d *docxDocument) writeSection(title string) {
+	d.paragraph("Heading1", []mdSpan{{text: title}})
+}
+
+// writeEntries writes each entry as a Heading 2 title, its subtitle and
+// detail lines, then the description and highlights
+func (d *docxDocument) writeEntries(entries []entry) {
+	for _, e := range entries {
+		if title := cleanText(e.title); title != "" {
+			d.paragraph("Heading2", []mdSpan{{text: title}})
+		}
+		// Details that are a web address are clickable, as in the PDF
+		for _, line := range e.lines() {
+			d.paragraph("EntryDetails", []mdSpan{line})
+		}
+		for _, block := range e.blocks() {
+			style := ""
+			if block.bullet {
+				style = "ListBullet"
+			}
+			d.paragraph(style, block.spans)
+		}
+	}
+}
+
+// writeText writes plain text as a Normal paragraph
+func (d *docxDocument) writeText(text string) {
+	d.paragraph("", []mdSpan{{text: text}})
+}
+
+// writeList writes one List Bullet paragraph per item
+func (d *docxDocument) writeList(items []string) {
+	for _, item := range items {
+		d.paragraph("ListBullet", []mdSpan{{text: item}})
+	}
+}
+
+// paragraph writes a paragraph with the given style ID, "" for Normal
+func (d *docxDocument) paragraph(style string, spans []mdSpan) {
+	d.body.WriteString("<w:p>")
+	if style != "" {
+		fmt.Fprintf(&d.body, `<w:pPr><w:pStyle w:val="%s"/></w:pPr>`, style)
+	}
+	for _, span := range spans {
+		if span.text == "" {
+			continue
+		}
+		if span.link == "" {
+			d.run(span)
+			continue
+		}
+		fmt.Fprintf(&d.body, `<w:hyperlink r:id="%s" w:history="1">`, d.link(span.link))
+		d.run(span)
+		d.body.WriteString("</w:hyperlink>")
+	}
+	d.body.WriteString("</w:p>")
+}
+
+// run writes a run of text with the span's character formatting
+func (d *docxDocument) run(span mdSpan) {
+	d.body.WriteString("<w:r>")
+	if span.link != "" || span.bold || span.italic {
+		d.body.WriteString("<w:rPr>")
+		if span.link != "" {
+			d.body.WriteString(`<w:rStyle w:val="Hyperlink"/>`)
+		}
+		if span.bold {
+			d.body.WriteString("<w:b/>")
+		}
+		if span.italic {
+			d.body.WriteString("<w:i/>")
+		}
+		d.body.WriteString("</w:rPr>")
+	}
+	d.body.WriteString(`<w:t xml:space="preserve">`)
+	d.body.WriteString(xmlEscape(span.text))
+	d.body.WriteString("</w:t></w:r>")
+}
+
+// link returns the relationship ID of a hyperlink target, adding it on
+// first use. IDs 1 and 2 are taken by the styles and numbering parts.
+func (d *docxDocument) link(target string) string {
+	if id, ok := d.links[target]; ok {
+		return id
+	}
+	id := fmt.Sprintf("rId%d", len(d.order)+3)
+	d.links[target] = id
+	d.order = append(d.order, target)
+	return id
+}
+
+// xml returns word/document.xml for a page of size twips and margins in mm
+func (d *docxDocument) xml(size [2]int, margin float64) string {
+	m := twips(margin)
+	return xml.Header +
+		`<w:document xmlns:w="` + docxNSMain + `" xmlns:r="` + docxNSRel + `"><w:body>` +
+		d.body.String() +
+		fmt.Sprintf(`<w:sectPr><w:pgSz w:w="%d" w:h="%d"/>`, size[0], size[1]) +
+		fmt.Sprintf(`<w:pgMar w:top="%d" w:right="%d" w:bottom="%d" w:left="%d" w:header="708" w:footer="708" w:gutter="0"/>`, m, m, m, m) +
+		`</w:sectPr></w:body></w:document>`
+}
+
+// rels returns word/_rels/document.xml.rels
+func (d *docxDocument) rels() string {
+	var b strings.Builder
+	b.WriteString(xml.Header + `<Relationships xmlns="` + docxNSPkgRel + `">`)
+	fmt.Fprintf(&b, `<Relationship Id="rId1" Type="%s" Target="styles.xml"/>`, docxRelStyles)
+	fmt.Fprintf(&b, `<Relationship Id="rId2" Type="%s" Target="numbering.xml"/>`, docxRelNumber)
+	for _, target := range d.order {
+		fmt.Fprintf(&b, `<Relationship Id="%s" Type="%s" Target="%s" TargetMode="External"/>`, d.links[target], docxRelLink, xmlEscape(target))
+	}
+	b.WriteString("</Relationships>")
+	return b.String()
+}
+
+// twips converts millimetres to twentieths of a point
+func twips(mm float64) int {
+	return int(mm/25.4*1440 + 0.5)
+}
+
+// hexColor returns c as the RRGGBB value used by OOXML
+func hexColor(c Color) string {
+	return fmt.Sprintf("%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// xmlEscape escapes text for element content and attribute values
+func xmlEscape(text string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
+
+const docxContentTypes = xml.Header +
+	`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+	`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+	`<Override PartName="/word/numbering.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"/>` +
+	`<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>` +
+	`<Override PartName="/docProps/app.xml" ContentType="application/vnd.openxmlformats-officedocument.extended-properties+xml"/>` +
+	`</Types>`
+
+const docxPackageRels = xml.Header +
+	`<Relationships xmlns="` + docxNSPkgRel + `">` +
+	`<Relationship Id="rId1" Type="` + docxRelDoc + `" Target="word/document.xml"/>` +
+	`<Relationship Id="rId2" Type="` + docxRelCore + `" Target="docProps/core.xml"/>` +
+	`<Relationship Id="rId3" Type="` + docxRelApp + `" Target="docProps/app.xml"/>` +
+	`</Relationships>`
+
+const docxAppProperties = xml.Header +
+	`<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties">` +
+	`<Application>CV Generator</Application>` +
+	`</Properties>`
+
+// docxNumbering defines the bullets of the List Bullet style
+const docxNumbering = xml.Header +
+	`<w:numbering xmlns:w="` + docxNSMain + `">` +
+	`<w:abstractNum w:abstractNumId="0"><w:multiLevelType w:val="singleLevel"/>` +
+	`<w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="bullet"/><w:pStyle w:val="ListBullet"/><w:lvlText w:val="•"/><w:lvlJc w:val="left"/>` +
+	`<w:pPr><w:ind w:left="360" w:hanging="360"/></w:pPr></w:lvl>` +
+	`</w:abstractNum>` +
+	`<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num>` +
+	`</w:numbering>`
+
+// docxCoreProperties returns docProps/core.xml with the same properties as
+// the PDF information dictionary
+func docxCoreProperties(meta docMetadata) string {
+	return xml.Header +
+		`<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/">` +
+		`<dc:title>` + xmlEscape(meta.title) + `</dc:title>` +
+		`<dc:subject>` + xmlEscape(meta.subject) + `</dc:subject>` +
+		`<dc:creator>` + xmlEscape(meta.author) + `</dc:creator>` +
+		`<cp:keywords>` + xmlEscape(strings.Join(meta.keywords, ", ")) + `</cp:keywords>` +
+		`</cp:coreProperties>`
+}
+
+// docxStyles returns word/styles.xml with the theme's colors. Sizes are in
+// half points and spacing in twentieths of a point.
+func docxStyles(theme Theme, lang string) string {
+	proofing, ok := docxLanguages[lang]
+	if !ok {
+		proofing = docxLanguages["en"]
+	}
+	text := hexColor(theme.Palette.Text)
+	light := hexColor(theme.Palette.LightText)
+	accent := hexColor(theme.Palette.Accent)
+	separator := hexColor(theme.Palette.Separator)
+
+	return xml.Header +
+		`<w:styles xmlns:w="` + docxNSMain + `">` +
+		`<w:docDefaults>` +
+		`<w:rPrDefault><w:rPr><w:rFonts w:ascii="` + docxFontFamily + `" w:hAnsi="` + docxFontFamily + `" w:eastAsia="` + docxFontFamily + `" w:cs="` + docxFontFamily + `"/>` +
+		`<w:color w:val="` + text + `"/><w:sz w:val="21"/><w:szCs w:val="21"/><w:lang w:val="` + proofing + `"/></w:rPr></w:rPrDefault>` +
+		`<w:pPrDefault><w:pPr><w:spacing w:after="80" w:line="264" w:lineRule="auto"/></w:pPr></w:pPrDefault>` +
+		`</w:docDefaults>` +
+		`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:qFormat/></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+		`<w:pPr><w:spacing w:after="60"/></w:pPr>` +
+		`<w:rPr><w:b/><w:color w:val="` + accent + `"/><w:sz w:val="40"/><w:szCs w:val="40"/></w:rPr></w:style>` +
+		`<w:style w:type="paragraph" w:customStyle="1" w:styleId="Contact"><w:name w:val="Contact"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+		`<w:pPr><w:spacing w:after="240"/></w:pPr>` +
+		`<w:rPr><w:color w:val="` + light + `"/><w:sz w:val="19"/><w:szCs w:val="19"/></w:rPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+		`<w:pPr><w:keepNext/><w:keepLines/><w:pBdr><w:bottom w:val="single" w:sz="6" w:space="1" w:color="` + separator + `"/></w:pBdr>` +
+		`<w:spacing w:before="240" w:after="120"/><w:outlineLvl w:val="0"/></w:pPr>` +
+		`<w:rPr><w:b/><w:color w:val="` + accent + `"/><w:sz w:val="24"/><w:szCs w:val="24"/></w:rPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+		`<w:pPr><w:keepNext/><w:keepLines/><w:spacing w:before="160" w:after="0"/><w:outlineLvl w:val="1"/></w:pPr>` +
+		`<w:rPr><w:b/><w:sz w:val="22"/><w:szCs w:val="22"/></w:rPr></w:style>` +
+		`<w:style w:type="paragraph" w:customStyle="1" w:styleId="EntryDetails"><w:name w:val="Entry Details"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+		`<w:pPr><w:keepNext/><w:spacing w:after="40"/></w:pPr>` +
+		`<w:rPr><w:i/><w:color w:val="` + light + `"/><w:sz w:val="19"/><w:szCs w:val="19"/></w:rPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="ListBullet"><w:name w:val="List Bullet"/><w:basedOn w:val="Normal"/><w:qFormat/>` +
+		`<w:pPr><w:numPr><w:numId w:val="1"/></w:numPr><w:spacing w:after="40"/><w:ind w:left="360" w:hanging="360"/></w:pPr></w:style>` +
+		`<w:style w:type="character" w:styleId="Hyperlink"><w:name w:val="Hyperlink"/>` +
+		`<w:rPr><w:color w:val="` + accent + `"/><w:u w:val="single"/></w:rPr></w:style>` +
+		`</w:styles>`
+}
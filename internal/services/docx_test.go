@@ -0,0 +1,189 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"cv-generator/internal/models"
+)
+
+// docxParts generates cv as DOCX and returns the contents of its parts by
+// name
+func docxParts(t *testing.T, cv models.CV) map[string]string {
+	t.Helper()
+	data, err := NewDOCXService().GenerateCV(context.Background(), cv)
+	if err != nil {
+		t.Fatalf("GenerateCV() error = %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("GenerateCV() is not a zip archive: %v", err)
+	}
+	parts := make(map[string]string, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[f.Name] = string(content)
+	}
+	return parts
+}
+
+// docxTestCV returns a CV with markup characters, non-Latin text and links
+func docxTestCV() models.CV {
+	return models.CV{
+		PersonalInfo: models.PersonalInfo{
+			FullName: "Zofia <Nowak> & Co",
+			Email:    "zofia@example.com",
+			Website:  "zofia.dev/?a=1&b=<2>",
+			GitHub:   "https://github.com/zofia",
+			Summary:  "Ζωή, Жизнь, 人生 & \"quotes\" <b>not bold</b>",
+		},
+		Experience: []models.Experience{{
+			Company: "R&D <Lab>", Position: "Инженер", StartDate: "2020-01",
+			Description: "See [docs](https://go.dev/doc?x=1&y=2) & **more**", Highlights: []string{"a < b", "Ölçek"},
+		}},
+		Projects:       []models.Project{{Name: "cvgen", URL: "https://github.com/zofia/cvgen"}},
+		Skills:         []models.Skill{{Name: "C++ & Go"}},
+		CustomSections: []models.CustomSection{{Title: "Ссылки & <more>", Content: "1 < 2"}},
+	}
+}
+
+func TestDOCXParts(t *testing.T) {
+	parts := docxParts(t, docxTestCV())
+	for _, name := range []string{
+		"[Content_Types].xml",
+		"_rels/.rels",
+		"docProps/core.xml",
+		"docProps/app.xml",
+		"word/document.xml",
+		"word/styles.xml",
+		"word/numbering.xml",
+		"word/_rels/document.xml.rels",
+	} {
+		t.Run(name, func(t *testing.T) {
+			content, ok := parts[name]
+			if !ok {
+				t.Fatalf("archive has no %s", name)
+			}
+			// Every part is well-formed XML
+			decoder := xml.NewDecoder(strings.NewReader(content))
+			for {
+				_, err := decoder.Token()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("%s is not well-formed: %v\n%s", name, err, content)
+				}
+			}
+		})
+	}
+
+	// The text is escaped, not dropped
+	for _, text := range []string{"Zofia &lt;Nowak&gt; &amp; Co", "Инженер", "人生", "Ссылки &amp; &lt;more&gt;"} {
+		if !strings.Contains(parts["word/document.xml"], text) {
+			t.Errorf("document.xml has no %q", text)
+		}
+	}
+}
+
+func TestDOCXRelationships(t *testing.T) {
+	parts := docxParts(t, docxTestCV())
+
+	type relationship struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	}
+	var rels struct {
+		Relationships []relationship `xml:"Relationship"`
+	}
+	if err := xml.Unmarshal([]byte(parts["word/_rels/document.xml.rels"]), &rels); err != nil {
+		t.Fatal(err)
+	}
+	targets := map[string]string{}
+	for _, rel := range rels.Relationships {
+		if _, dup := targets[rel.ID]; dup {
+			t.Errorf("relationship %s defined twice", rel.ID)
+		}
+		targets[rel.ID] = rel.Target
+	}
+
+	// Collect the r:id of every hyperlink in the document
+	var ids []string
+	decoder := xml.NewDecoder(strings.NewReader(parts["word/document.xml"]))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "hyperlink" {
+			continue
+		}
+		for _, attr := range start.Attr {
+			if attr.Name.Space == docxNSRel && attr.Name.Local == "id" {
+				ids = append(ids, attr.Value)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		t.Fatal("document.xml has no hyperlinks")
+	}
+	for _, id := range ids {
+		if _, ok := targets[id]; !ok {
+			t.Errorf("hyperlink %s has no relationship", id)
+		}
+	}
+
+	// The decoded targets are the links as written
+	for _, want := range []string{"https://zofia.dev/?a=1&b=<2>", "https://go.dev/doc?x=1&y=2", "https://github.com/zofia/cvgen"} {
+		found := false
+		for _, target := range targets {
+			found = found || target == want
+		}
+		if !found {
+			t.Errorf("relationships %v have no target %q", targets, want)
+		}
+	}
+	// The styles and numbering parts keep their IDs
+	if targets["rId1"] != "styles.xml" || targets["rId2"] != "numbering.xml" {
+		t.Errorf("relationships = %v, want rId1 styles.xml and rId2 numbering.xml", targets)
+	}
+}
+
+func TestDOCXSectionTitles(t *testing.T) {
+	cv := longCV(1)
+	cv.Language = "es"
+	cv.Layout = []models.SectionLayout{{Section: models.SectionExperience, Title: "Berufserfahrung"}}
+	cv.CustomSections = []models.CustomSection{{Title: "Open source", Content: "cvgen"}}
+	parts := docxParts(t, cv)
+	document := parts["word/document.xml"]
+
+	for _, title := range []string{"Berufserfahrung", "Open source"} {
+		want := `<w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t xml:space="preserve">` + title + `</w:t>`
+		if !strings.Contains(document, want) {
+			t.Errorf("document.xml = %s\nwant a Heading 1 %q", document, title)
+		}
+	}
+	if strings.Contains(document, "EXPERIENCIA") {
+		t.Errorf("document.xml has the translated title instead of the override")
+	}
+	if strings.Contains(parts["word/styles.xml"], "<w:caps/>") {
+		t.Errorf("styles.xml changes the case of the headings")
+	}
+}
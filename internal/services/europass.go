@@ -0,0 +1,1103 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"html/template"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"cv-generator/internal/models"
+)
+
+// Europass is the subset of the Europass CV data model (SkillsPassport,
+// XML schema V3.x) that the converter reads and writes. The same types
+// encode Europass XML and Europass JSON; XML wraps lists in a *List
+// element and writes dates as attributes.
+type Europass struct {
+	XMLName      xml.Name              `xml:"SkillsPassport" json:"-"`
+	Xmlns        string                `xml:"xmlns,attr,omitempty" json:"-"`
+	Locale       string                `xml:"locale,attr,omitempty" json:"Locale,omitempty"`
+	DocumentInfo *EuropassDocumentInfo `xml:"DocumentInfo,omitempty" json:"DocumentInfo,omitempty"`
+	LearnerInfo  EuropassLearnerInfo   `xml:"LearnerInfo" json:"LearnerInfo"`
+}
+
+// EuropassDocument is the envelope of a Europass JSON document
+type EuropassDocument struct {
+	SkillsPassport *Europass `json:"SkillsPassport"`
+}
+
+type EuropassDocumentInfo struct {
+	DocumentType string `xml:"DocumentType,omitempty" json:"DocumentType,omitempty"`
+	CreationDate string `xml:"CreationDate,omitempty" json:"CreationDate,omitempty"`
+	XSDVersion   string `xml:"XSDVersion,omitempty" json:"XSDVersion,omitempty"`
+	Generator    string `xml:"Generator,omitempty" json:"Generator,omitempty"`
+}
+
+type EuropassLearnerInfo struct {
+	Identification EuropassIdentification            `xml:"Identification" json:"Identification"`
+	Headline       *EuropassHeadline                 `xml:"Headline,omitempty" json:"Headline,omitempty"`
+	WorkExperience EuropassList[EuropassWork]        `xml:"WorkExperienceList,omitempty" json:"WorkExperience,omitempty"`
+	Education      EuropassList[EuropassEducation]   `xml:"EducationList,omitempty" json:"Education,omitempty"`
+	Skills         *EuropassSkills                   `xml:"Skills,omitempty" json:"Skills,omitempty"`
+	Achievement    EuropassList[EuropassAchievement] `xml:"AchievementList,omitempty" json:"Achievement,omitempty"`
+}
+
+type EuropassIdentification struct {
+	PersonName   EuropassPersonName   `xml:"PersonName" json:"PersonName"`
+	ContactInfo  *EuropassContactInfo `xml:"ContactInfo,omitempty" json:"ContactInfo,omitempty"`
+	Demographics *struct{}            `xml:"Demographics,omitempty" json:"Demographics,omitempty"`
+	Photo        *struct{}            `xml:"Photo,omitempty" json:"Photo,omitempty"`
+}
+
+type EuropassPersonName struct {
+	FirstName string `xml:"FirstName,omitempty" json:"FirstName,omitempty"`
+	Surname   string `xml:"Surname,omitempty" json:"Surname,omitempty"`
+}
+
+type EuropassContactInfo struct {
+	Address   *EuropassAddress                 `xml:"Address,omitempty" json:"Address,omitempty"`
+	Email     *EuropassContact                 `xml:"Email,omitempty" json:"Email,omitempty"`
+	Telephone EuropassList[EuropassUseContact] `xml:"TelephoneList,omitempty" json:"Telephone,omitempty"`
+	Website   EuropassList[EuropassUseContact] `xml:"WebsiteList,omitempty" json:"Website,omitempty"`
+}
+
+type EuropassAddress struct {
+	Contact EuropassAddressContact `xml:"Contact" json:"Contact"`
+}
+
+type EuropassAddressContact struct {
+	AddressLine  string        `xml:"AddressLine,omitempty" json:"AddressLine,omitempty"`
+	PostalCode   string        `xml:"PostalCode,omitempty" json:"PostalCode,omitempty"`
+	Municipality string        `xml:"Municipality,omitempty" json:"Municipality,omitempty"`
+	Country      *EuropassCode `xml:"Country,omitempty" json:"Country,omitempty"`
+}
+
+type EuropassContact struct {
+	Contact string `xml:"Contact" json:"Contact"`
+}
+
+// EuropassUseContact is a telephone number or website with its use, such
+// as "mobile" or "personal"
+type EuropassUseContact struct {
+	Contact string        `xml:"Contact" json:"Contact"`
+	Use     *EuropassCode `xml:"Use,omitempty" json:"Use,omitempty"`
+}
+
+// EuropassCode is a Europass code list value with its label in the
+// document locale
+type EuropassCode struct {
+	Code  string `xml:"Code,omitempty" json:"Code,omitempty"`
+	Label string `xml:"Label,omitempty" json:"Label,omitempty"`
+}
+
+type EuropassHeadline struct {
+	Type        EuropassCode `xml:"Type" json:"Type"`
+	Description EuropassCode `xml:"Description" json:"Description"`
+}
+
+// EuropassList is a list that Europass XML wraps in an element named
+// after its items, such as TelephoneList around Telephone elements. Europass
+// JSON writes it as a plain array.
+type EuropassList[T any] []T
+
+// MarshalXML writes the items inside start, each named after start
+// without its "List" suffix. Empty lists are left out.
+func (l EuropassList[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if len(l) == 0 {
+		return nil
+	}
+	item := xml.StartElement{Name: xml.Name{Local: strings.TrimSuffix(start.Name.Local, "List")}}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, v := range l {
+		if err := e.EncodeElement(v, item); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+func (l *EuropassList[T]) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	var list struct {
+		Items []T `xml:",any"`
+	}
+	if err := dec.DecodeElement(&list, &start); err != nil {
+		return err
+	}
+	*l = append(*l, list.Items...)
+	return nil
+}
+
+// EuropassDate is a year, month or day precision date
+type EuropassDate struct {
+	Year  int `json:"Year,omitempty"`
+	Month int `json:"Month,omitempty"`
+	Day   int `json:"Day,omitempty"`
+}
+
+type EuropassPeriod struct {
+	From    *EuropassDate `xml:"From,omitempty" json:"From,omitempty"`
+	To      *EuropassDate `xml:"To,omitempty" json:"To,omitempty"`
+	Current bool          `xml:"Current,omitempty" json:"Current,omitempty"`
+}
+
+type EuropassOrganisation struct {
+	Name        string               `xml:"Name,omitempty" json:"Name,omitempty"`
+	ContactInfo *EuropassContactInfo `xml:"ContactInfo,omitempty" json:"ContactInfo,omitempty"`
+}
+
+// EuropassWork is a work experience entry. Activities is rich text, a
+// small HTML subset.
+type EuropassWork struct {
+	Period     EuropassPeriod        `xml:"Period" json:"Period"`
+	Position   *EuropassCode         `xml:"Position,omitempty" json:"Position,omitempty"`
+	Activities string                `xml:"Activities,omitempty" json:"Activities,omitempty"`
+	Employer   *EuropassOrganisation `xml:"Employer,omitempty" json:"Employer,omitempty"`
+}
+
+type EuropassEducation struct {
+	Period       EuropassPeriod        `xml:"Period" json:"Period"`
+	Title        string                `xml:"Title,omitempty" json:"Title,omitempty"`
+	Activities   string                `xml:"Activities,omitempty" json:"Activities,omitempty"`
+	Organisation *EuropassOrganisation `xml:"Organisation,omitempty" json:"Organisation,omitempty"`
+	Level        *EuropassCode         `xml:"Level,omitempty" json:"Level,omitempty"`
+	Field        *EuropassCode         `xml:"Field,omitempty" json:"Field,omitempty"`
+}
+
+type EuropassSkills struct {
+	Linguistic     *EuropassLinguistic `xml:"Linguistic,omitempty" json:"Linguistic,omitempty"`
+	Communication  *EuropassSkill      `xml:"Communication,omitempty" json:"Communication,omitempty"`
+	Organisational *EuropassSkill      `xml:"Organisational,omitempty" json:"Organisational,omitempty"`
+	JobRelated     *EuropassSkill      `xml:"JobRelated,omitempty" json:"JobRelated,omitempty"`
+	Computer       *EuropassComputer   `xml:"Computer,omitempty" json:"Computer,omitempty"`
+	Driving        *EuropassDriving    `xml:"Driving,omitempty" json:"Driving,omitempty"`
+	Other          *EuropassSkill      `xml:"Other,omitempty" json:"Other,omitempty"`
+}
+
+// EuropassSkill is a skill category described in rich text
+type EuropassSkill struct {
+	Description string `xml:"Description,omitempty" json:"Description,omitempty"`
+}
+
+type EuropassLinguistic struct {
+	MotherTongue    EuropassList[EuropassMotherTongue]    `xml:"MotherTongueList,omitempty" json:"MotherTongue,omitempty"`
+	ForeignLanguage EuropassList[EuropassForeignLanguage] `xml:"ForeignLanguageList,omitempty" json:"ForeignLanguage,omitempty"`
+}
+
+type EuropassMotherTongue struct {
+	Description EuropassCode `xml:"Description" json:"Description"`
+}
+
+type EuropassForeignLanguage struct {
+	Description      EuropassCode           `xml:"Description" json:"Description"`
+	ProficiencyLevel *EuropassLanguageLevel `xml:"ProficiencyLevel,omitempty" json:"ProficiencyLevel,omitempty"`
+	VerifiedBy       *EuropassVerifiedBy    `xml:"VerifiedBy,omitempty" json:"VerifiedBy,omitempty"`
+}
+
+type EuropassVerifiedBy struct {
+	Certificate []EuropassLanguageCertificate `xml:"Certificate" json:"Certificate"`
+}
+
+// EuropassLanguageLevel is the CEFR self-assessment grid, one level per
+// skill
+type EuropassLanguageLevel struct {
+	Listening         string `xml:"Listening,omitempty" json:"Listening,omitempty"`
+	Reading           string `xml:"Reading,omitempty" json:"Reading,omitempty"`
+	SpokenInteraction string `xml:"SpokenInteraction,omitempty" json:"SpokenInteraction,omitempty"`
+	SpokenProduction  string `xml:"SpokenProduction,omitempty" json:"SpokenProduction,omitempty"`
+	Writing           string `xml:"Writing,omitempty" json:"Writing,omitempty"`
+}
+
+type EuropassLanguageCertificate struct {
+	Title        string `xml:"Title,omitempty" json:"Title,omitempty"`
+	AwardingBody string `xml:"AwardingBody,omitempty" json:"AwardingBody,omitempty"`
+	Level        string `xml:"Level,omitempty" json:"Level,omitempty"`
+}
+
+// EuropassComputer is the digital skills category, with the optional
+// self-assessment grid of the EU digital competence framework
+type EuropassComputer struct {
+	Description      string                `xml:"Description,omitempty" json:"Description,omitempty"`
+	ProficiencyLevel *EuropassDigitalLevel `xml:"ProficiencyLevel,omitempty" json:"ProficiencyLevel,omitempty"`
+}
+
+type EuropassDigitalLevel struct {
+	Information     string `xml:"Information,omitempty" json:"Information,omitempty"`
+	Communication   string `xml:"Communication,omitempty" json:"Communication,omitempty"`
+	ContentCreation string `xml:"ContentCreation,omitempty" json:"ContentCreation,omitempty"`
+	Safety          string `xml:"Safety,omitempty" json:"Safety,omitempty"`
+	ProblemSolving  string `xml:"ProblemSolving,omitempty" json:"ProblemSolving,omitempty"`
+}
+
+// EuropassDriving lists driving licence categories such as "B"
+type EuropassDriving struct {
+	Description []string `xml:"Description" json:"Description"`
+}
+
+// EuropassAchievement is an entry of the additional information section,
+// such as a project or a publication
+type EuropassAchievement struct {
+	Title       EuropassCode `xml:"Title" json:"Title"`
+	Description string       `xml:"Description,omitempty" json:"Description,omitempty"`
+}
+
+// MarshalXML writes the date as Europass attributes: year="2020",
+// month="--03" and day="---15"
+func (d EuropassDate) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if d.Year > 0 {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "year"}, Value: strconv.Itoa(d.Year)})
+	}
+	if d.Month > 0 {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "month"}, Value: fmt.Sprintf("--%02d", d.Month)})
+	}
+	if d.Day > 0 {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "day"}, Value: fmt.Sprintf("---%02d", d.Day)})
+	}
+	return e.EncodeElement(struct{}{}, start)
+}
+
+func (d *EuropassDate) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		n, err := strconv.Atoi(strings.TrimLeft(strings.TrimSpace(attr.Value), "-"))
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", attr.Name.Local, attr.Value)
+		}
+		switch attr.Name.Local {
+		case "year":
+			d.Year = n
+		case "month":
+			d.Month = n
+		case "day":
+			d.Day = n
+		}
+	}
+	return dec.Skip()
+}
+
+// Europass constants written on export
+const (
+	europassNamespace  = "http://europass.cedefop.europa.eu/Europass"
+	europassXSDVersion = "V3.3"
+	europassGenerator  = "CV Generator"
+)
+
+// Europass achievement codes of the built-in CV sections. Volunteer work
+// has no code of its own and is written with a label only.
+var europassAchievementCodes = map[string]string{
+	models.SectionProjects:       "projects",
+	models.SectionCertifications: "certifications",
+	models.SectionPublications:   "publications",
+	models.SectionAwards:         "honors_awards",
+}
+
+// europassSkillTitles are the translation keys of the Europass skill
+// categories that become custom sections on import
+var europassSkillTitles = struct {
+	communication, organisational, jobRelated, driving, other string
+}{
+	communication:  "Communication skills",
+	organisational: "Organisational skills",
+	jobRelated:     "Job-related skills",
+	driving:        "Driving licence",
+	other:          "Other skills",
+}
+
+type EuropassService struct{}
+
+func NewEuropassService() *EuropassService {
+	return &EuropassService{}
+}
+
+// Import converts a Europass XML or Europass JSON document into a
+// models.CV; the format is detected from the first character. Fields the
+// CV model cannot represent are listed in the returned warnings.
+func (s *EuropassService) Import(data []byte) (models.CV, []ConversionWarning, error) {
+	var doc Europass
+	switch trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))); {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		if err := xml.Unmarshal(trimmed, &doc); err != nil {
+			return models.CV{}, nil, fmt.Errorf("invalid Europass XML document: %w", err)
+		}
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var envelope EuropassDocument
+		if err := json.Unmarshal(trimmed, &envelope); err != nil {
+			return models.CV{}, nil, fmt.Errorf("invalid Europass JSON document: %w", err)
+		}
+		if envelope.SkillsPassport == nil {
+			return models.CV{}, nil, fmt.Errorf("invalid Europass JSON document: missing SkillsPassport")
+		}
+		doc = *envelope.SkillsPassport
+	default:
+		return models.CV{}, nil, fmt.Errorf("document is neither Europass XML nor Europass JSON")
+	}
+
+	var cv models.CV
+	var warnings []ConversionWarning
+	warn := func(field, format string, args ...interface{}) {
+		warnings = append(warnings, ConversionWarning{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Locale, e.g. "es" or "en_GB"
+	if doc.Locale != "" {
+		locale, _, _ := strings.Cut(strings.ToLower(strings.ReplaceAll(doc.Locale, "-", "_")), "_")
+		if _, ok := translations[locale]; ok {
+			cv.Language = locale
+		} else {
+			warn("Locale", "locale %q is not supported, the CV is written in English", doc.Locale)
+		}
+	}
+	lang := cv.Language
+
+	// Identification
+	learner := doc.LearnerInfo
+	id := learner.Identification
+	cv.PersonalInfo.FullName = joinNonEmpty(" ", id.PersonName.FirstName, id.PersonName.Surname)
+	if id.Demographics != nil {
+		warn("LearnerInfo.Identification.Demographics", "birth date, gender and nationality are not supported")
+	}
+	if id.Photo != nil {
+		warn("LearnerInfo.Identification.Photo", "profile picture is not supported")
+	}
+	if contact := id.ContactInfo; contact != nil {
+		path := "LearnerInfo.Identification.ContactInfo"
+		if contact.Email != nil {
+			cv.PersonalInfo.Email = contact.Email.Contact
+		}
+		for i, phone := range contact.Telephone {
+			if i == 0 {
+				cv.PersonalInfo.Phone = phone.Contact
+			} else {
+				warn(fmt.Sprintf("%s.Telephone[%d]", path, i), "only one telephone number is supported")
+			}
+		}
+		if address := contact.Address; address != nil {
+			a := address.Contact
+			var country string
+			if a.Country != nil {
+				country = europassCodeLabel(*a.Country)
+			}
+			cv.PersonalInfo.Location = joinNonEmpty(", ", a.Municipality, country)
+			if cv.PersonalInfo.Location == "" {
+				cv.PersonalInfo.Location = a.AddressLine
+			} else if a.AddressLine != "" || a.PostalCode != "" {
+				warn(path+".Address", "street address and postal code are not supported")
+			}
+		}
+		for i, website := range contact.Website {
+			if !addProfileLink(&cv.PersonalInfo, strings.TrimSpace(website.Contact)) {
+				warn(fmt.Sprintf("%s.Website[%d]", path, i), "only one personal website is supported")
+			}
+		}
+	}
+
+	if headline := learner.Headline; headline != nil {
+		if headline.Type.Code == "personal_statement" {
+			cv.PersonalInfo.Summary = headline.Description.Label
+		} else {
+			warn("LearnerInfo.Headline", "%s headline %q is not supported", headline.Type.Code, headline.Description.Label)
+		}
+	}
+
+	// Work experience
+	for i, work := range learner.WorkExperience {
+		path := fmt.Sprintf("LearnerInfo.WorkExperience[%d]", i)
+		exp := models.Experience{
+			StartDate:   work.Period.From.String(),
+			Description: htmlMarkdown(work.Activities),
+		}
+		if !work.Period.Current {
+			exp.EndDate = work.Period.To.String()
+		}
+		if work.Position != nil {
+			exp.Position = work.Position.Label
+		}
+		if work.Employer != nil {
+			exp.Company = work.Employer.Name
+			if work.Employer.ContactInfo != nil {
+				warn(path+".Employer.ContactInfo", "employer address and website are not supported")
+			}
+		}
+		cv.Experience = append(cv.Experience, exp)
+	}
+
+	// Education and training
+	for i, edu := range learner.Education {
+		path := fmt.Sprintf("LearnerInfo.Education[%d]", i)
+		education := models.Education{
+			Degree:      edu.Title,
+			StartDate:   edu.Period.From.String(),
+			Description: htmlMarkdown(edu.Activities),
+		}
+		if !edu.Period.Current {
+			education.EndDate = edu.Period.To.String()
+		}
+		if edu.Organisation != nil {
+			education.Institution = edu.Organisation.Name
+			if edu.Organisation.ContactInfo != nil {
+				warn(path+".Organisation.ContactInfo", "institution address and website are not supported")
+			}
+		}
+		if edu.Level != nil {
+			warn(path+".Level", "EQF level is not supported")
+		}
+		if edu.Field != nil {
+			warn(path+".Field", "field of study is not supported")
+		}
+		cv.Education = append(cv.Education, education)
+	}
+
+	if skills := learner.Skills; skills != nil {
+		path := "LearnerInfo.Skills"
+
+		// Languages: mother tongues are native, foreign languages take the
+		// median level of their self-assessment grid
+		if linguistic := skills.Linguistic; linguistic != nil {
+			for _, tongue := range linguistic.MotherTongue {
+				cv.Languages = append(cv.Languages, models.Language{
+					Name:  europassCodeLabel(tongue.Description),
+					Code:  tongue.Description.Code,
+					Level: models.LevelNative,
+				})
+			}
+			for i, foreign := range linguistic.ForeignLanguage {
+				languagePath := fmt.Sprintf("%s.Linguistic.ForeignLanguage[%d]", path, i)
+				language := models.Language{
+					Name: europassCodeLabel(foreign.Description),
+					Code: foreign.Description.Code,
+				}
+				if grid := foreign.ProficiencyLevel; grid != nil {
+					var levels []string
+					var ranks []int
+					for _, value := range []string{grid.Listening, grid.Reading, grid.SpokenInteraction, grid.SpokenProduction, grid.Writing} {
+						if value == "" {
+							continue
+						}
+						level, ok := models.ParseLanguageLevel(value)
+						if !ok {
+							warn(languagePath+".ProficiencyLevel", "level %q does not match a CEFR level", value)
+							continue
+						}
+						levels = append(levels, level)
+						ranks = append(ranks, models.LevelRank(level))
+					}
+					if len(ranks) > 0 {
+						sort.Ints(ranks)
+						language.Level = models.LanguageLevels[ranks[(len(ranks)-1)/2]-1]
+						if ranks[0] != ranks[len(ranks)-1] {
+							warn(languagePath+".ProficiencyLevel", "self-assessment grid %s summarized as %s",
+								strings.Join(levels, "/"), language.Level)
+						}
+					}
+				}
+				if foreign.VerifiedBy != nil {
+					var certificates []string
+					for _, certificate := range foreign.VerifiedBy.Certificate {
+						certificates = append(certificates, joinNonEmpty(" ", certificate.Title, certificate.Level))
+					}
+					language.Certificate = joinNonEmpty(", ", certificates...)
+				}
+				cv.Languages = append(cv.Languages, language)
+			}
+		}
+
+		// Digital skills are a list of "Name (Level)" items
+		if computer := skills.Computer; computer != nil {
+			for _, item := range europassSkillItems(computer.Description) {
+				skill := models.Skill{Name: item}
+				if open := strings.LastIndex(item, " ("); open > 0 && strings.HasSuffix(item, ")") {
+					skill = models.Skill{Name: item[:open], Level: item[open+2 : len(item)-1]}
+				}
+				cv.Skills = append(cv.Skills, skill)
+			}
+			if computer.ProficiencyLevel != nil {
+				warn(path+".Computer.ProficiencyLevel", "digital competence self-assessment grid is not supported")
+			}
+		}
+
+		// Other categories have no counterpart and are kept as text
+		categories := []struct {
+			skill *EuropassSkill
+			title string
+		}{
+			{skills.Communication, europassSkillTitles.communication},
+			{skills.Organisational, europassSkillTitles.organisational},
+			{skills.JobRelated, europassSkillTitles.jobRelated},
+			{skills.Other, europassSkillTitles.other},
+		}
+		for _, category := range categories {
+			if category.skill == nil {
+				continue
+			}
+			if content := htmlText(category.skill.Description); content != "" {
+				cv.CustomSections = append(cv.CustomSections, models.CustomSection{
+					Title:   translate(category.title, lang),
+					Content: content,
+				})
+			}
+		}
+		if skills.Driving != nil && len(skills.Driving.Description) > 0 {
+			cv.CustomSections = append(cv.CustomSections, models.CustomSection{
+				Title:   translate(europassSkillTitles.driving, lang),
+				Content: strings.Join(skills.Driving.Description, ", "),
+			})
+		}
+	}
+
+	// Achievements become custom sections, one per title. Descriptions that
+	// start with a bold title, as written by Export, become entries.
+	sections := make(map[string]int)
+	for _, achievement := range learner.Achievement {
+		title := achievement.Title.Label
+		if title == "" {
+			title = europassLabel(strings.ReplaceAll(achievement.Title.Code, "_", " "))
+			for section, code := range europassAchievementCodes {
+				if code == achievement.Title.Code {
+					title = europassLabel(sectionTitle(models.SectionLayout{Section: section}, lang))
+				}
+			}
+		}
+		i, ok := sections[title]
+		if !ok {
+			i = len(cv.CustomSections)
+			sections[title] = i
+			cv.CustomSections = append(cv.CustomSections, models.CustomSection{Title: title})
+		}
+		section := &cv.CustomSections[i]
+
+		blocks := htmlBlocks(achievement.Description)
+		if len(blocks) == 0 || !allSpans(blocks[0], func(span mdSpan) bool { return span.bold }) {
+			section.Content = joinNonEmpty("\n", section.Content, blocksText(blocks))
+			continue
+		}
+		e := models.CustomEntry{Title: blocksText(blocks[:1])}
+		blocks = blocks[1:]
+		if len(blocks) > 0 && allSpans(blocks[0], func(span mdSpan) bool { return span.italic }) {
+			e.Subtitle, blocks = blocksText(blocks[:1]), blocks[1:]
+		}
+		e.Description = descriptionMarkdown(blocks)
+		section.Entries = append(section.Entries, e)
+	}
+	for i, section := range cv.CustomSections {
+		if section.Content != "" && len(section.Entries) > 0 {
+			warn("LearnerInfo.Achievement", "%s: text without a bold title was not imported next to the entries", section.Title)
+			cv.CustomSections[i].Content = ""
+		}
+	}
+
+	return cv, warnings, nil
+}
+
+// Export converts a models.CV into a Europass document. Sections that
+// Europass has no structure for are written as achievements, and listed in
+// the returned warnings.
+func (s *EuropassService) Export(cv models.CV) (Europass, []ConversionWarning) {
+	var warnings []ConversionWarning
+	warn := func(field, message string) {
+		warnings = append(warnings, ConversionWarning{Field: field, Message: message})
+	}
+	lang := cv.Language
+	if lang == "" {
+		lang = "en"
+	}
+	period := func(field, startDate, endDate string) EuropassPeriod {
+		var p EuropassPeriod
+		for _, date := range []struct {
+			value string
+			to    **EuropassDate
+		}{{startDate, &p.From}, {endDate, &p.To}} {
+			if strings.TrimSpace(date.value) == "" {
+				continue
+			}
+			if *date.to = europassDate(date.value); *date.to == nil {
+				warn(field, fmt.Sprintf("date %q is not in YYYY, YYYY-MM or YYYY-MM-DD format", date.value))
+			}
+		}
+		p.Current = p.From != nil && strings.TrimSpace(endDate) == ""
+		return p
+	}
+
+	doc := Europass{
+		Xmlns:  europassNamespace,
+		Locale: lang,
+		DocumentInfo: &EuropassDocumentInfo{
+			DocumentType: "ECV",
+			XSDVersion:   europassXSDVersion,
+			Generator:    europassGenerator,
+		},
+	}
+	if !cv.CreatedAt.IsZero() {
+		doc.DocumentInfo.CreationDate = cv.CreatedAt.UTC().Format(time.RFC3339)
+	}
+
+	// Identification
+	p := cv.PersonalInfo
+	learner := &doc.LearnerInfo
+	first, last, _ := strings.Cut(strings.TrimSpace(p.FullName), " ")
+	learner.Identification.PersonName = EuropassPersonName{FirstName: first, Surname: strings.TrimSpace(last)}
+	contact := &EuropassContactInfo{}
+	if p.Location != "" {
+		contact.Address = &EuropassAddress{Contact: EuropassAddressContact{Municipality: p.Location}}
+	}
+	if p.Email != "" {
+		contact.Email = &EuropassContact{Contact: p.Email}
+	}
+	if p.Phone != "" {
+		contact.Telephone = EuropassList[EuropassUseContact]{{Contact: p.Phone, Use: &EuropassCode{Code: "mobile"}}}
+	}
+	for _, website := range []struct{ link, use string }{
+		{p.Website, "personal"},
+		{p.LinkedIn, "business"},
+		{p.GitHub, "portfolio"},
+	} {
+		if website.link != "" {
+			contact.Website = append(contact.Website, EuropassUseContact{Contact: website.link, Use: &EuropassCode{Code: website.use}})
+		}
+	}
+	if contact.Address != nil || contact.Email != nil || len(contact.Telephone) > 0 || len(contact.Website) > 0 {
+		learner.Identification.ContactInfo = contact
+	}
+
+	if p.Summary != "" {
+		learner.Headline = &EuropassHeadline{
+			Type:        EuropassCode{Code: "personal_statement", Label: translate("Personal statement", lang)},
+			Description: EuropassCode{Label: p.Summary},
+		}
+	}
+
+	for i, exp := range cv.Experience {
+		work := EuropassWork{
+			Period:     period(fmt.Sprintf("experience[%d]", i), exp.StartDate, exp.EndDate),
+			Activities: string(markdownHTML(exp.Description, exp.Highlights)),
+		}
+		if exp.Position != "" {
+			work.Position = &EuropassCode{Label: exp.Position}
+		}
+		if exp.Company != "" {
+			work.Employer = &EuropassOrganisation{Name: exp.Company}
+		}
+		learner.WorkExperience = append(learner.WorkExperience, work)
+	}
+
+	for i, edu := range cv.Education {
+		education := EuropassEducation{
+			Period:     period(fmt.Sprintf("education[%d]", i), edu.StartDate, edu.EndDate),
+			Title:      edu.Degree,
+			Activities: string(markdownHTML(edu.Description, edu.Highlights)),
+		}
+		if edu.Institution != "" {
+			education.Organisation = &EuropassOrganisation{Name: edu.Institution}
+		}
+		learner.Education = append(learner.Education, education)
+	}
+
+	skills := &EuropassSkills{}
+
+	// Native languages are mother tongues; the level of the others fills
+	// the whole self-assessment grid
+	if len(cv.Languages) > 0 {
+		skills.Linguistic = &EuropassLinguistic{}
+	}
+	for _, language := range cv.Languages {
+		description := EuropassCode{Code: language.Code, Label: language.Name}
+		if language.Level == models.LevelNative {
+			skills.Linguistic.MotherTongue = append(skills.Linguistic.MotherTongue, EuropassMotherTongue{Description: description})
+			continue
+		}
+		foreign := EuropassForeignLanguage{Description: description}
+		if language.Level != "" {
+			foreign.ProficiencyLevel = &EuropassLanguageLevel{
+				Listening:         language.Level,
+				Reading:           language.Level,
+				SpokenInteraction: language.Level,
+				SpokenProduction:  language.Level,
+				Writing:           language.Level,
+			}
+		}
+		if language.Certificate != "" {
+			foreign.VerifiedBy = &EuropassVerifiedBy{Certificate: []EuropassLanguageCertificate{{Title: language.Certificate}}}
+		}
+		skills.Linguistic.ForeignLanguage = append(skills.Linguistic.ForeignLanguage, foreign)
+	}
+
+	if len(cv.Skills) > 0 {
+		var b strings.Builder
+		b.WriteString("<ul>")
+		for _, skill := range cv.Skills {
+			item := skill.Name
+			if skill.Level != "" {
+				item += " (" + skill.Level + ")"
+			}
+			b.WriteString("<li>" + template.HTMLEscapeString(item) + "</li>")
+		}
+		b.WriteString("</ul>")
+		skills.Computer = &EuropassComputer{Description: b.String()}
+	}
+
+	// Built-in sections without a Europass structure
+	for _, section := range []string{
+		models.SectionProjects,
+		models.SectionCertifications,
+		models.SectionPublications,
+		models.SectionAwards,
+		models.SectionVolunteer,
+	} {
+		entries, _ := sectionEntries(cv, section)
+		if len(entries) == 0 {
+			continue
+		}
+		title := EuropassCode{
+			Code:  europassAchievementCodes[section],
+			Label: europassLabel(sectionTitle(models.SectionLayout{Section: section}, lang)),
+		}
+		for _, e := range entries {
+			learner.Achievement = append(learner.Achievement, EuropassAchievement{Title: title, Description: entryHTML(e)})
+		}
+		warn(section, "exported as Europass achievements, with dates and details as text")
+	}
+
+	// Custom sections named after a Europass skill category, as Import
+	// creates them, go back to that category
+	categories := map[string]**EuropassSkill{
+		translate(europassSkillTitles.communication, lang):  &skills.Communication,
+		translate(europassSkillTitles.organisational, lang): &skills.Organisational,
+		translate(europassSkillTitles.jobRelated, lang):     &skills.JobRelated,
+		translate(europassSkillTitles.other, lang):          &skills.Other,
+	}
+	for i, section := range cv.CustomSections {
+		title := strings.TrimSpace(section.Title)
+		if len(section.Entries) == 0 {
+			if category, ok := categories[title]; ok && *category == nil {
+				*category = &EuropassSkill{Description: textHTML(section.Content)}
+				continue
+			}
+			if title == translate(europassSkillTitles.driving, lang) && skills.Driving == nil {
+				skills.Driving = &EuropassDriving{}
+				for _, licence := range strings.Split(section.Content, ",") {
+					if licence = strings.TrimSpace(licence); licence != "" {
+						skills.Driving.Description = append(skills.Driving.Description, licence)
+					}
+				}
+				continue
+			}
+			learner.Achievement = append(learner.Achievement, EuropassAchievement{
+				Title:       EuropassCode{Label: title},
+				Description: textHTML(section.Content),
+			})
+			warn(fmt.Sprintf("customSections[%d]", i), "exported as a Europass achievement")
+			continue
+		}
+		for _, e := range customEntries(section, lang) {
+			learner.Achievement = append(learner.Achievement, EuropassAchievement{
+				Title:       EuropassCode{Label: title},
+				Description: entryHTML(e),
+			})
+		}
+		warn(fmt.Sprintf("customSections[%d]", i), "exported as Europass achievements, with dates as text")
+	}
+
+	if *skills != (EuropassSkills{}) {
+		learner.Skills = skills
+	}
+	return doc, warnings
+}
+
+// EncodeXML returns doc as an indented Europass XML document
+func (s *EuropassService) EncodeXML(doc Europass) ([]byte, error) {
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+// String returns the date in the CV format: YYYY, YYYY-MM or YYYY-MM-DD
+func (d *EuropassDate) String() string {
+	switch {
+	case d == nil || d.Year <= 0:
+		return ""
+	case d.Month < 1 || d.Month > 12:
+		return fmt.Sprintf("%04d", d.Year)
+	case d.Day < 1 || d.Day > 31:
+		return fmt.Sprintf("%04d-%02d", d.Year, d.Month)
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// europassDate converts a CV date keeping its precision, or returns nil if
+// value is not a valid date
+func europassDate(value string) *EuropassDate {
+	value = strings.TrimSpace(value)
+	t, ok := models.ParseDate(value)
+	if !ok {
+		return nil
+	}
+	d := &EuropassDate{Year: t.Year()}
+	switch strings.Count(value, "-") {
+	case 2:
+		d.Day = t.Day()
+		fallthrough
+	case 1:
+		d.Month = int(t.Month())
+	}
+	return d
+}
+
+// europassCodeLabel returns the label of a code, or the code itself
+func europassCodeLabel(code EuropassCode) string {
+	if code.Label != "" {
+		return code.Label
+	}
+	return code.Code
+}
+
+// europassLabel returns a section title in sentence case, as Europass
+// labels are written: "PROYECTOS" becomes "Proyectos"
+func europassLabel(title string) string {
+	r, n := utf8.DecodeRuneInString(title)
+	return string(unicode.ToUpper(r)) + strings.ToLower(title[n:])
+}
+
+// addProfileLink stores link as the LinkedIn profile, the GitHub profile
+// or the personal website, going by its host. It returns false when that
+// field is already set.
+func addProfileLink(info *models.PersonalInfo, link string) bool {
+	var field *string
+	switch host := strings.ToLower(linkHost(link)); {
+	case host == "linkedin.com" || strings.HasSuffix(host, ".linkedin.com"):
+		field = &info.LinkedIn
+	case host == "github.com":
+		field = &info.GitHub
+	default:
+		field = &info.Website
+	}
+	if *field != "" {
+		return false
+	}
+	*field = link
+	return true
+}
+
+// linkHost returns the host of a web address, with or without a scheme
+func linkHost(link string) string {
+	if !strings.Contains(link, "://") {
+		link = "https://" + link
+	}
+	host := strings.TrimPrefix(link[strings.Index(link, "://")+3:], "www.")
+	if end := strings.IndexAny(host, "/?#:"); end >= 0 {
+		host = host[:end]
+	}
+	return host
+}
+
+// entryHTML writes an entry as Europass rich text: the title in bold, the
+// subtitle in italics, the details and then the description. Import reads
+// entries back from this layout.
+func entryHTML(e entry) string {
+	var b strings.Builder
+	if title := cleanText(e.title); title != "" {
+		b.WriteString("<p><strong>" + template.HTMLEscapeString(title) + "</strong></p>")
+	}
+	lines := e.lines()
+	if cleanText(e.subtitle) != "" {
+		b.WriteString("<p><em>" + template.HTMLEscapeString(lines[0].text) + "</em></p>")
+		lines = lines[1:]
+	}
+	for _, line := range lines {
+		b.WriteString("<p>")
+		writeSpanHTML(&b, line)
+		b.WriteString("</p>")
+	}
+	b.WriteString(string(markdownHTML(cleanText(e.description), e.highlights)))
+	return b.String()
+}
+
+// textHTML writes plain text as rich text, one paragraph per line
+func textHTML(text string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			b.WriteString("<p>" + template.HTMLEscapeString(line) + "</p>")
+		}
+	}
+	return b.String()
+}
+
+// htmlMarkdown converts Europass rich text to description Markdown
+func htmlMarkdown(text string) string {
+	return descriptionMarkdown(htmlBlocks(text))
+}
+
+// htmlText converts Europass rich text to plain text, one line per
+// paragraph or bullet
+func htmlText(text string) string {
+	return blocksText(htmlBlocks(text))
+}
+
+// blocksText returns the text of blocks without styles, one line each
+func blocksText(blocks []mdBlock) string {
+	var lines []string
+	for _, block := range blocks {
+		var b strings.Builder
+		for _, span := range block.spans {
+			b.WriteString(span.text)
+		}
+		lines = append(lines, b.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// europassSkillItems splits a skills description into items: its bullets,
+// or else the comma, semicolon or line separated parts of its text
+func europassSkillItems(text string) []string {
+	var items []string
+	for _, block := range htmlBlocks(text) {
+		line := blocksText([]mdBlock{block})
+		parts := []string{line}
+		if !block.bullet {
+			parts = strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ';' })
+		}
+		for _, part := range parts {
+			if part = strings.TrimSpace(part); part != "" {
+				items = append(items, part)
+			}
+		}
+	}
+	return items
+}
+
+// allSpans reports whether block is a paragraph whose spans all satisfy f
+func allSpans(block mdBlock, f func(mdSpan) bool) bool {
+	if block.bullet {
+		return false
+	}
+	for _, span := range block.spans {
+		if strings.TrimSpace(span.text) != "" && !f(span) {
+			return false
+		}
+	}
+	return true
+}
+
+var hrefPattern = regexp.MustCompile(`(?i)\bhref\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+
+// htmlBlocks parses Europass rich text, a small HTML subset, into
+// paragraphs and bullets with bold, italic and link spans. Other tags are
+// dropped and their text is kept.
+func htmlBlocks(text string) []mdBlock {
+	var blocks []mdBlock
+	var spans []mdSpan
+	var buf strings.Builder
+	bold, italic := 0, 0
+	bullet := false
+	link := ""
+
+	flushSpan := func() {
+		if buf.Len() > 0 {
+			spans = append(spans, mdSpan{text: html.UnescapeString(buf.String()), bold: bold > 0, italic: italic > 0, link: link})
+			buf.Reset()
+		}
+	}
+	flushBlock := func() {
+		flushSpan()
+		if spans = collapseSpaces(spans); len(spans) > 0 {
+			blocks = append(blocks, mdBlock{bullet: bullet, spans: spans})
+		}
+		spans = nil
+	}
+	nest := func(depth int, closing bool) int {
+		if !closing {
+			return depth + 1
+		}
+		if depth > 0 {
+			return depth - 1
+		}
+		return 0
+	}
+
+	for {
+		start := strings.IndexByte(text, '<')
+		if start < 0 {
+			buf.WriteString(text)
+			break
+		}
+		end := strings.IndexByte(text[start:], '>')
+		if end < 0 {
+			buf.WriteString(text)
+			break
+		}
+		buf.WriteString(text[:start])
+		tag := text[start+1 : start+end]
+		text = text[start+end+1:]
+
+		closing := strings.HasPrefix(tag, "/")
+		fields := strings.Fields(strings.Trim(tag, "/"))
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToLower(fields[0]) {
+		case "p", "div", "ul", "ol", "h1", "h2", "h3", "h4", "h5", "h6":
+			flushBlock()
+			bullet = false
+		case "br":
+			flushBlock()
+		case "li":
+			flushBlock()
+			bullet = !closing
+		case "strong", "b":
+			flushSpan()
+			bold = nest(bold, closing)
+		case "em", "i":
+			flushSpan()
+			italic = nest(italic, closing)
+		case "a":
+			flushSpan()
+			link = ""
+			if m := hrefPattern.FindStringSubmatch(tag); m != nil && !closing {
+				link = safeLink(strings.TrimSpace(html.UnescapeString(m[1] + m[2])))
+			}
+		}
+	}
+	flushBlock()
+
+	return blocks
+}
+
+// collapseSpaces collapses runs of white space to one space, as HTML is
+// displayed, and drops the spaces at both ends. Spans left without text
+// are removed.
+func collapseSpaces(spans []mdSpan) []mdSpan {
+	var kept []mdSpan
+	space := true
+	for _, span := range spans {
+		var b strings.Builder
+		for _, r := range span.text {
+			if unicode.IsSpace(r) {
+				if !space {
+					b.WriteByte(' ')
+				}
+				space = true
+				continue
+			}
+			b.WriteRune(r)
+			space = false
+		}
+		if b.Len() > 0 {
+			span.text = b.String()
+			kept = append(kept, span)
+		}
+	}
+	if n := len(kept); n > 0 {
+		kept[n-1].text = strings.TrimRight(kept[n-1].text, " ")
+	}
+	return kept
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cv-generator/internal/models"
+)
+
+// europassCV returns a CV made only of data Europass can represent
+func europassCV() models.CV {
+	return models.CV{
+		Language: "en",
+		PersonalInfo: models.PersonalInfo{
+			FullName: "Zofia Nowak",
+			Email:    "zofia@example.com",
+			Phone:    "+48 600 123 456",
+			Location: "Kraków",
+			LinkedIn: "https://www.linkedin.com/in/zofia",
+			GitHub:   "https://github.com/zofia",
+			Website:  "https://zofia.dev",
+			Summary:  "Backend engineer",
+		},
+		Experience: []models.Experience{
+			{
+				Company: "Acme", Position: "Engineer", StartDate: "2020-01",
+				Description: "Built **payments**", Highlights: []string{"Cut latency"},
+			},
+			{Company: "Initech", Position: "Intern", StartDate: "2018-06-01", EndDate: "2019"},
+		},
+		Education: []models.Education{
+			{Institution: "UJ", Degree: "MSc Physics", StartDate: "2015", EndDate: "2020", Description: "Thesis on [lasers](https://uj.edu.pl)"},
+		},
+		Languages: []models.Language{
+			{Name: "English", Level: models.LevelC1},
+		},
+		CustomSections: []models.CustomSection{
+			{Title: "Interests", Content: "Chess and climbing"},
+		},
+	}
+}
+
+func TestEuropassRoundTrip(t *testing.T) {
+	s := NewEuropassService()
+	encodings := []struct {
+		name   string
+		encode func(doc Europass) ([]byte, error)
+	}{
+		{"xml", s.EncodeXML},
+		{"json", func(doc Europass) ([]byte, error) {
+			return json.Marshal(EuropassDocument{SkillsPassport: &doc})
+		}},
+	}
+	for _, enc := range encodings {
+		t.Run(enc.name, func(t *testing.T) {
+			cv := europassCV()
+			doc, _ := s.Export(cv)
+			data, err := enc.encode(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, warnings, err := s.Import(data)
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+			if len(warnings) > 0 {
+				t.Errorf("Import() warnings = %v", warnings)
+			}
+			// Europass has no highlights; they come back as bullets of
+			// the description
+			want := europassCV()
+			want.Experience[0].Description, want.Experience[0].Highlights = "Built **payments**\n\n- Cut latency", nil
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip = %+v\nwant %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestEuropassExportWarnings(t *testing.T) {
+	tests := []struct {
+		name  string
+		cv    models.CV
+		field string
+	}{
+		{"text custom section", models.CV{CustomSections: []models.CustomSection{{Title: "Interests", Content: "Chess"}}}, "customSections[0]"},
+		{"custom section entries", models.CV{CustomSections: []models.CustomSection{{Title: "Talks", Entries: []models.CustomEntry{{Title: "GopherCon"}}}}}, "customSections[0]"},
+		{"bad date", models.CV{Experience: []models.Experience{{Company: "A", Position: "B", StartDate: "soon"}}}, "experience[0]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, warnings := NewEuropassService().Export(tt.cv); !hasWarning(warnings, tt.field) {
+				t.Errorf("Export() warnings = %v, want one for %s", warnings, tt.field)
+			}
+		})
+	}
+}
+
+func TestEuropassExportSkillCategory(t *testing.T) {
+	cv := models.CV{CustomSections: []models.CustomSection{{Title: "Communication skills", Content: "Public speaking"}}}
+	doc, warnings := NewEuropassService().Export(cv)
+	if len(warnings) > 0 {
+		t.Errorf("Export() warnings = %v, want none for a Europass skill category", warnings)
+	}
+	if doc.LearnerInfo.Skills == nil || doc.LearnerInfo.Skills.Communication == nil {
+		t.Fatalf("Export() skills = %+v, want communication skills", doc.LearnerInfo.Skills)
+	}
+}
+
+func TestEuropassImportInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"not europass", "name: x", "neither Europass XML nor Europass JSON"},
+		{"malformed xml", "<SkillsPassport><LearnerInfo>", "invalid Europass XML"},
+		{"json without envelope", `{"LearnerInfo": {}}`, "missing SkillsPassport"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := NewEuropassService().Import([]byte(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Import() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"embed"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Bundled DejaVu Sans Condensed TrueType fonts. They cover Latin Extended,
+// Greek and Cyrillic, so names and descriptions outside Windows-1252 render
+// correctly. gofpdf subsets UTF-8 fonts on output, so only the glyphs
+// actually used end up in the generated PDF.
+//
+//go:embed fonts/*.ttf
+var fontFiles embed.FS
+
+// fontFamily is the family name the bundled fonts are registered under
+const fontFamily = "DejaVu"
+
+var fontStyles = []struct {
+	style string
+	file  string
+}{
+	{"", "fonts/DejaVuSansCondensed.ttf"},
+	{"B", "fonts/DejaVuSansCondensed-Bold.ttf"},
+	{"I", "fonts/DejaVuSansCondensed-Oblique.ttf"},
+	{"BI", "fonts/DejaVuSansCondensed-BoldOblique.ttf"},
+}
+
+// fontData holds the contents of fontStyles, read from fontFiles once per
+// process. Render builds the document several times while fitting it to the
+// page limit, so the files are not copied out of the embedded FS each time.
+var fontData struct {
+	once  sync.Once
+	files [][]byte
+	err   error
+}
+
+// loadFonts returns the font files in fontStyles order
+func loadFonts() ([][]byte, error) {
+	fontData.once.Do(func() {
+		for _, fs := range fontStyles {
+			data, err := fontFiles.ReadFile(fs.file)
+			if err != nil {
+				fontData.err = fmt.Errorf("failed to load font %s: %w", fs.file, err)
+				return
+			}
+			fontData.files = append(fontData.files, data)
+		}
+	})
+	return fontData.files, fontData.err
+}
+
+// registerFonts adds the regular, bold, italic and bold-italic UTF-8 fonts
+// to the document
+func registerFonts(pdf *gofpdf.Fpdf) error {
+	files, err := loadFonts()
+	if err != nil {
+		return err
+	}
+	for i, fs := range fontStyles {
+		pdf.AddUTF8FontFromBytes(fontFamily, fs.style, files[i])
+	}
+	return pdf.Error()
+}
+
+// bmpText replaces characters outside the Basic Multilingual Plane, such as
+// emoji, with U+FFFD. gofpdf keeps the glyph widths of UTF-8 fonts in a
+// 16-bit table and panics on output when a page uses one of them.
+func bmpText(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r > 0xFFFF {
+			return unicode.ReplacementChar
+		}
+		return r
+	}, s)
+}
+
+// mapStrings returns a copy of v with f applied to every string in its
+// structs and slices. Unexported fields, such as those of time.Time, are
+// copied as they are.
+func mapStrings[T any](v T, f func(string) string) T {
+	return mapValue(reflect.ValueOf(v), f).Interface().(T)
+}
+
+func mapValue(v reflect.Value, f func(string) string) reflect.Value {
+	out := reflect.New(v.Type()).Elem()
+	out.Set(v)
+	switch v.Kind() {
+	case reflect.String:
+		out.SetString(f(v.String()))
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if out.Field(i).CanSet() {
+				out.Field(i).Set(mapValue(v.Field(i), f))
+			}
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			out.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+			for i := 0; i < v.Len(); i++ {
+				out.Index(i).Set(mapValue(v.Index(i), f))
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cv-generator/internal/models"
+)
+
+func TestRenderUnicode(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"polish", "Zażółć gęślą jaźń"},
+		{"greek", "Γεια σου κόσμε"},
+		{"cyrillic", "Съешь же ещё этих мягких булок"},
+		{"glyph missing from the font", "Go 漢字 🚀"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cv := models.CV{
+				PersonalInfo: models.PersonalInfo{FullName: tt.text, Email: "zofia@example.com", Summary: tt.text},
+				Experience:   []models.Experience{{Company: tt.text, Position: tt.text, StartDate: "2020", Description: "**" + tt.text + "** *" + tt.text + "*"}},
+				Skills:       []models.Skill{{Name: tt.text}},
+			}
+			pdf, info, err := NewPDFService().Render(context.Background(), cv)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if len(pdf) == 0 || info.Pages != 1 {
+				t.Errorf("Render() = %d bytes, %d pages, want one page", len(pdf), info.Pages)
+			}
+		})
+	}
+}
+
+func TestBMPText(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Zażółć", "Zażółć"},
+		{"漢字", "漢字"},
+		{"Go 🚀!", "Go \uFFFD!"},
+		{"𝔊o", "\uFFFDo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := bmpText(tt.input); got != tt.want {
+				t.Errorf("bmpText(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapStrings(t *testing.T) {
+	created := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	cv := models.CV{
+		PersonalInfo: models.PersonalInfo{FullName: "Zofia 🚀"},
+		Experience:   []models.Experience{{Company: "Acme 🚀", Highlights: []string{"🚀"}}},
+		FitPages:     2,
+		CreatedAt:    created,
+	}
+	got := mapStrings(cv, bmpText)
+	if got.PersonalInfo.FullName != "Zofia \uFFFD" || got.Experience[0].Company != "Acme \uFFFD" || got.Experience[0].Highlights[0] != "\uFFFD" {
+		t.Errorf("mapStrings() = %+v, want every string mapped", got)
+	}
+	if got.FitPages != 2 || !got.CreatedAt.Equal(created) || got.Skills != nil {
+		t.Errorf("mapStrings() = %+v, want other fields unchanged", got)
+	}
+	if cv.Experience[0].Highlights[0] != "🚀" {
+		t.Errorf("mapStrings() changed the original: %+v", cv.Experience)
+	}
+}
+
+func TestLoadFonts(t *testing.T) {
+	first, err := loadFonts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != len(fontStyles) {
+		t.Fatalf("loadFonts() = %d files, want %d", len(first), len(fontStyles))
+	}
+	second, _ := loadFonts()
+	for i := range first {
+		if len(first[i]) == 0 || &first[i][0] != &second[i][0] {
+			t.Errorf("loadFonts() file %d is empty or read again", i)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"log/slog"
+	"strings"
+
+	"cv-generator/internal/models"
+	"cv-generator/web"
+)
+
+// HTMLService renders a CV as a self-contained HTML document using the
+// embedded web/cv/cv_template.html layout. All user content is escaped by
+// html/template.
+type HTMLService struct {
+	tmpl *template.Template
+}
+
+func NewHTMLService() *HTMLService {
+	tmpl := template.Must(template.New("cv").Funcs(templateFuncs("en")).Parse(web.CVTemplate))
+	return &HTMLService{tmpl: tmpl}
+}
+
+// templateFuncs returns the functions available to the CV template, bound to
+// the target language
+func templateFuncs(lang string) template.FuncMap {
+	if lang == "" {
+		lang = "en"
+	}
+	return template.FuncMap{
+		"t":     func(text string) string { return translate(text, lang) },
+		"lang":  func() string { return lang },
+		"level": func(level string) string { return languageLevelLabel(level, lang) },
+		"join":  strings.Join,
+		// markdown renders a description and its highlights with the
+		// subset supported by the PDF
+		"markdown": markdownHTML,
+		"sectionTitle": func(layout models.SectionLayout) string {
+			return sectionTitle(layout, lang)
+		},
+	}
+}
+
+// GenerateCV renders cv as an HTML document. ctx only carries logging
+// attributes such as the request ID.
+func (s *HTMLService) GenerateCV(ctx context.Context, cv models.CV) ([]byte, error) {
+	slog.DebugContext(ctx, "generating HTML", "language", cv.Language)
+
+	tmpl, err := s.tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := tmpl.Funcs(templateFuncs(cv.Language)).Execute(buffer, &cv); err != nil {
+		return nil, err
+	}
+
+	slog.DebugContext(ctx, "HTML generated", "bytes", buffer.Len())
+	return buffer.Bytes(), nil
+}
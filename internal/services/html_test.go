@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"cv-generator/internal/models"
+)
+
+func TestHTMLDates(t *testing.T) {
+	sections := []struct {
+		name string
+		cv   func(start, end string) models.CV
+	}{
+		{"experience", func(start, end string) models.CV {
+			return models.CV{Experience: []models.Experience{{Company: "Acme", Position: "Engineer", StartDate: start, EndDate: end}}}
+		}},
+		{"education", func(start, end string) models.CV {
+			return models.CV{Education: []models.Education{{Institution: "UJ", Degree: "MSc", StartDate: start, EndDate: end}}}
+		}},
+		{"volunteer", func(start, end string) models.CV {
+			return models.CV{Volunteer: []models.Volunteer{{Organization: "Red Cross", StartDate: start, EndDate: end}}}
+		}},
+		{"projects", func(start, end string) models.CV {
+			return models.CV{Projects: []models.Project{{Name: "cvgen", StartDate: start, EndDate: end}}}
+		}},
+	}
+	dates := []struct {
+		name  string
+		start string
+		end   string
+		want  string // empty when no date line is expected
+	}{
+		{"no dates", "", "", ""},
+		{"ongoing", "2020-01", "", "2020-01 - Present"},
+		{"finished", "2020-01", "2022-06", "2020-01 - 2022-06"},
+	}
+	for _, section := range sections {
+		for _, d := range dates {
+			t.Run(section.name+"/"+d.name, func(t *testing.T) {
+				cv := section.cv(d.start, d.end)
+				cv.PersonalInfo = models.PersonalInfo{FullName: "Zofia Nowak"}
+				out, err := NewHTMLService().GenerateCV(context.Background(), cv)
+				if err != nil {
+					t.Fatal(err)
+				}
+				html := string(out)
+				if d.want == "" {
+					if strings.Contains(html, "Present") || strings.Contains(html, `<div class="item-subtitle">`) {
+						t.Errorf("GenerateCV() = %s\nwant no date line", html)
+					}
+					return
+				}
+				if !strings.Contains(html, d.want) {
+					t.Errorf("GenerateCV() = %s\nwant it to contain %q", html, d.want)
+				}
+			})
+		}
+	}
+}
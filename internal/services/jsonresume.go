@@ -0,0 +1,523 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"cv-generator/internal/models"
+)
+
+// JSONResume is the subset of the JSON Resume schema (https://jsonresume.org/schema)
+// that the converter reads and writes. Sections without a models.CV
+// counterpart are kept as raw JSON so they can be reported as warnings.
+type JSONResume struct {
+	Schema       string                  `json:"$schema,omitempty"`
+	Basics       JSONResumeBasics        `json:"basics"`
+	Work         []JSONResumeWork        `json:"work,omitempty"`
+	Education    []JSONResumeEdu         `json:"education,omitempty"`
+	Skills       []JSONResumeSkill       `json:"skills,omitempty"`
+	Languages    []JSONResumeLang        `json:"languages,omitempty"`
+	Volunteer    []JSONResumeVolunteer   `json:"volunteer,omitempty"`
+	Awards       []JSONResumeAward       `json:"awards,omitempty"`
+	Certificates []JSONResumeCertificate `json:"certificates,omitempty"`
+	Publications []JSONResumePublication `json:"publications,omitempty"`
+	Interests    []json.RawMessage       `json:"interests,omitempty"`
+	References   []json.RawMessage       `json:"references,omitempty"`
+	Projects     []JSONResumeProject     `json:"projects,omitempty"`
+	Meta         *JSONResumeMetadata     `json:"meta,omitempty"`
+}
+
+type JSONResumeBasics struct {
+	Name     string              `json:"name"`
+	Label    string              `json:"label,omitempty"`
+	Image    string              `json:"image,omitempty"`
+	Email    string              `json:"email,omitempty"`
+	Phone    string              `json:"phone,omitempty"`
+	URL      string              `json:"url,omitempty"`
+	Summary  string              `json:"summary,omitempty"`
+	Location *JSONResumeLocation `json:"location,omitempty"`
+	Profiles []JSONResumeProfile `json:"profiles,omitempty"`
+}
+
+type JSONResumeLocation struct {
+	Address     string `json:"address,omitempty"`
+	PostalCode  string `json:"postalCode,omitempty"`
+	City        string `json:"city,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+	Region      string `json:"region,omitempty"`
+}
+
+type JSONResumeProfile struct {
+	Network  string `json:"network"`
+	Username string `json:"username,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+type JSONResumeWork struct {
+	Name        string   `json:"name"`
+	Company     string   `json:"company,omitempty"` // pre-1.0 schema name for Name
+	Position    string   `json:"position"`
+	URL         string   `json:"url,omitempty"`
+	Location    string   `json:"location,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Highlights  []string `json:"highlights,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+type JSONResumeEdu struct {
+	Institution string   `json:"institution"`
+	URL         string   `json:"url,omitempty"`
+	Area        string   `json:"area,omitempty"`
+	StudyType   string   `json:"studyType,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	Score       string   `json:"score,omitempty"`
+	Courses     []string `json:"courses,omitempty"`
+}
+
+type JSONResumeSkill struct {
+	Name     string   `json:"name"`
+	Level    string   `json:"level,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+type JSONResumeLang struct {
+	Language string `json:"language"`
+	Fluency  string `json:"fluency,omitempty"`
+}
+
+type JSONResumeProject struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Highlights  []string `json:"highlights,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	Entity      string   `json:"entity,omitempty"`
+	Type        string   `json:"type,omitempty"`
+}
+
+type JSONResumeCertificate struct {
+	Name   string `json:"name"`
+	Date   string `json:"date,omitempty"`
+	Issuer string `json:"issuer,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+type JSONResumeAward struct {
+	Title   string `json:"title"`
+	Date    string `json:"date,omitempty"`
+	Awarder string `json:"awarder,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+type JSONResumePublication struct {
+	Name        string `json:"name"`
+	Publisher   string `json:"publisher,omitempty"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Summary     string `json:"summary,omitempty"`
+}
+
+type JSONResumeVolunteer struct {
+	Organization string   `json:"organization"`
+	Position     string   `json:"position,omitempty"`
+	URL          string   `json:"url,omitempty"`
+	StartDate    string   `json:"startDate,omitempty"`
+	EndDate      string   `json:"endDate,omitempty"`
+	Summary      string   `json:"summary,omitempty"`
+	Highlights   []string `json:"highlights,omitempty"`
+}
+
+type JSONResumeMetadata struct {
+	Canonical    string `json:"canonical,omitempty"`
+	Version      string `json:"version,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// ConversionWarning reports data that could not be carried over when
+// converting between models.CV and an external format
+type ConversionWarning struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+const jsonResumeSchemaURL = "https://raw.githubusercontent.com/jsonresume/resume-schema/v1.0.0/schema.json"
+
+type JSONResumeService struct{}
+
+func NewJSONResumeService() *JSONResumeService {
+	return &JSONResumeService{}
+}
+
+// Import converts a JSON Resume document into a models.CV. Fields the CV
+// model cannot represent are listed in the returned warnings.
+func (s *JSONResumeService) Import(data []byte) (models.CV, []ConversionWarning, error) {
+	var resume JSONResume
+	if err := json.Unmarshal(data, &resume); err != nil {
+		return models.CV{}, nil, fmt.Errorf("invalid JSON Resume document: %w", err)
+	}
+
+	var cv models.CV
+	var warnings []ConversionWarning
+	warn := func(field, format string, args ...interface{}) {
+		warnings = append(warnings, ConversionWarning{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Basics
+	b := resume.Basics
+	cv.PersonalInfo = models.PersonalInfo{
+		FullName: b.Name,
+		Email:    b.Email,
+		Phone:    b.Phone,
+		Website:  b.URL,
+		Summary:  b.Summary,
+	}
+	if b.Label != "" {
+		warn("basics.label", "headline %q is not supported", b.Label)
+	}
+	if b.Image != "" {
+		warn("basics.image", "profile picture is not supported")
+	}
+	if loc := b.Location; loc != nil {
+		cv.PersonalInfo.Location = joinNonEmpty(", ", loc.City, loc.Region, loc.CountryCode)
+		if cv.PersonalInfo.Location == "" {
+			cv.PersonalInfo.Location = loc.Address
+		} else if loc.Address != "" || loc.PostalCode != "" {
+			warn("basics.location", "street address and postal code are not supported")
+		}
+	}
+	for i, profile := range b.Profiles {
+		link := profile.URL
+		switch strings.ToLower(profile.Network) {
+		case "linkedin":
+			if link == "" && profile.Username != "" {
+				link = "https://www.linkedin.com/in/" + profile.Username
+			}
+			cv.PersonalInfo.LinkedIn = link
+		case "github":
+			if link == "" && profile.Username != "" {
+				link = "https://github.com/" + profile.Username
+			}
+			cv.PersonalInfo.GitHub = link
+		default:
+			warn(fmt.Sprintf("basics.profiles[%d]", i), "%s profile is not supported", profile.Network)
+		}
+	}
+
+	// Work
+	for i, work := range resume.Work {
+		path := fmt.Sprintf("work[%d]", i)
+		company := work.Name
+		if company == "" {
+			company = work.Company
+		}
+		description := work.Summary
+		if description == "" {
+			description = work.Description
+		} else if work.Description != "" {
+			warn(path+".description", "company description is not supported")
+		}
+		cv.Experience = append(cv.Experience, models.Experience{
+			Company:     company,
+			Position:    work.Position,
+			StartDate:   work.StartDate,
+			EndDate:     work.EndDate,
+			Description: description,
+			Highlights:  work.Highlights,
+		})
+		if work.URL != "" {
+			warn(path+".url", "company URL is not supported")
+		}
+		if work.Location != "" {
+			warn(path+".location", "job location is not supported")
+		}
+	}
+
+	// Education
+	for i, edu := range resume.Education {
+		path := fmt.Sprintf("education[%d]", i)
+		cv.Education = append(cv.Education, models.Education{
+			Institution: edu.Institution,
+			Degree:      joinNonEmpty(", ", edu.StudyType, edu.Area),
+			StartDate:   edu.StartDate,
+			EndDate:     edu.EndDate,
+		})
+		if edu.Score != "" {
+			warn(path+".score", "score is not supported")
+		}
+		if len(edu.Courses) > 0 {
+			warn(path+".courses", "%d course(s) not supported", len(edu.Courses))
+		}
+		if edu.URL != "" {
+			warn(path+".url", "institution URL is not supported")
+		}
+	}
+
+	// Skills
+	for i, skill := range resume.Skills {
+		cv.Skills = append(cv.Skills, models.Skill{Name: skill.Name, Level: skill.Level})
+		if len(skill.Keywords) > 0 {
+			warn(fmt.Sprintf("skills[%d].keywords", i), "%d keyword(s) not supported", len(skill.Keywords))
+		}
+	}
+
+	// Languages
+	for i, lang := range resume.Languages {
+		language := models.Language{Name: lang.Language}
+		if level, ok := models.ParseLanguageLevel(lang.Fluency); ok {
+			language.Level = level
+		} else if lang.Fluency != "" {
+			warn(fmt.Sprintf("languages[%d].fluency", i), "fluency %q does not match a CEFR level", lang.Fluency)
+		}
+		cv.Languages = append(cv.Languages, language)
+	}
+
+	// Projects
+	for i, project := range resume.Projects {
+		path := fmt.Sprintf("projects[%d]", i)
+		cv.Projects = append(cv.Projects, models.Project{
+			Name:         project.Name,
+			Role:         strings.Join(project.Roles, ", "),
+			URL:          project.URL,
+			Technologies: project.Keywords,
+			StartDate:    project.StartDate,
+			EndDate:      project.EndDate,
+			Description:  project.Description,
+		})
+		if len(project.Highlights) > 0 {
+			warn(path+".highlights", "%d highlight(s) not supported", len(project.Highlights))
+		}
+		if project.Entity != "" || project.Type != "" {
+			warn(path, "project entity and type are not supported")
+		}
+	}
+
+	// Certificates
+	for _, cert := range resume.Certificates {
+		cv.Certifications = append(cv.Certifications, models.Certification{
+			Name:          cert.Name,
+			Issuer:        cert.Issuer,
+			Date:          cert.Date,
+			CredentialURL: cert.URL,
+		})
+	}
+
+	// Awards
+	for _, award := range resume.Awards {
+		cv.Awards = append(cv.Awards, models.Award{
+			Title:       award.Title,
+			Issuer:      award.Awarder,
+			Date:        award.Date,
+			Description: award.Summary,
+		})
+	}
+
+	// Publications
+	for _, publication := range resume.Publications {
+		cv.Publications = append(cv.Publications, models.Publication{
+			Title:       publication.Name,
+			Publisher:   publication.Publisher,
+			Date:        publication.ReleaseDate,
+			URL:         publication.URL,
+			Description: publication.Summary,
+		})
+	}
+
+	// Volunteer
+	for i, volunteer := range resume.Volunteer {
+		path := fmt.Sprintf("volunteer[%d]", i)
+		cv.Volunteer = append(cv.Volunteer, models.Volunteer{
+			Organization: volunteer.Organization,
+			Role:         volunteer.Position,
+			StartDate:    volunteer.StartDate,
+			EndDate:      volunteer.EndDate,
+			Description:  volunteer.Summary,
+		})
+		if len(volunteer.Highlights) > 0 {
+			warn(path+".highlights", "%d highlight(s) not supported", len(volunteer.Highlights))
+		}
+		if volunteer.URL != "" {
+			warn(path+".url", "organization URL is not supported")
+		}
+	}
+
+	// Sections without a models.CV counterpart
+	unsupported := []struct {
+		name    string
+		entries []json.RawMessage
+	}{
+		{"interests", resume.Interests},
+		{"references", resume.References},
+	}
+	for _, section := range unsupported {
+		if len(section.entries) > 0 {
+			warn(section.name, "section is not supported, %d item(s) not imported", len(section.entries))
+		}
+	}
+
+	return cv, warnings, nil
+}
+
+// Export converts a models.CV into a JSON Resume document. Fields the
+// JSON Resume schema cannot represent are listed in the returned warnings.
+func (s *JSONResumeService) Export(cv models.CV) (JSONResume, []ConversionWarning) {
+	var warnings []ConversionWarning
+	warn := func(field, message string) {
+		warnings = append(warnings, ConversionWarning{Field: field, Message: message})
+	}
+
+	p := cv.PersonalInfo
+	resume := JSONResume{
+		Schema: jsonResumeSchemaURL,
+		Basics: JSONResumeBasics{
+			Name:    p.FullName,
+			Email:   p.Email,
+			Phone:   p.Phone,
+			URL:     p.Website,
+			Summary: p.Summary,
+		},
+	}
+	if p.Location != "" {
+		resume.Basics.Location = &JSONResumeLocation{Address: p.Location}
+	}
+	if p.LinkedIn != "" {
+		resume.Basics.Profiles = append(resume.Basics.Profiles, JSONResumeProfile{Network: "LinkedIn", URL: p.LinkedIn})
+	}
+	if p.GitHub != "" {
+		resume.Basics.Profiles = append(resume.Basics.Profiles, JSONResumeProfile{Network: "GitHub", URL: p.GitHub})
+	}
+
+	for _, exp := range cv.Experience {
+		resume.Work = append(resume.Work, JSONResumeWork{
+			Name:       exp.Company,
+			Position:   exp.Position,
+			StartDate:  exp.StartDate,
+			EndDate:    exp.EndDate,
+			Summary:    exp.Description,
+			Highlights: exp.Highlights,
+		})
+	}
+
+	for i, edu := range cv.Education {
+		resume.Education = append(resume.Education, JSONResumeEdu{
+			Institution: edu.Institution,
+			StudyType:   edu.Degree,
+			StartDate:   edu.StartDate,
+			EndDate:     edu.EndDate,
+		})
+		if edu.Description != "" {
+			warn(fmt.Sprintf("education[%d].description", i), "JSON Resume education entries have no description")
+		}
+		if len(edu.Highlights) > 0 {
+			warn(fmt.Sprintf("education[%d].highlights", i), "JSON Resume education entries have no highlights")
+		}
+	}
+
+	for _, skill := range cv.Skills {
+		resume.Skills = append(resume.Skills, JSONResumeSkill{Name: skill.Name, Level: skill.Level})
+	}
+
+	// Levels are exported as their English description ("Upper
+	// intermediate"), which Import maps back to the same CEFR level
+	for i, lang := range cv.Languages {
+		resume.Languages = append(resume.Languages, JSONResumeLang{
+			Language: lang.Name,
+			Fluency:  translate(lang.Level, "en"),
+		})
+		if lang.Code != "" {
+			warn(fmt.Sprintf("languages[%d].code", i), "JSON Resume languages have no ISO code")
+		}
+		if lang.Certificate != "" {
+			warn(fmt.Sprintf("languages[%d].certificate", i), "JSON Resume languages have no certificate")
+		}
+	}
+
+	for _, project := range cv.Projects {
+		var roles []string
+		if project.Role != "" {
+			roles = []string{project.Role}
+		}
+		resume.Projects = append(resume.Projects, JSONResumeProject{
+			Name:        project.Name,
+			Description: project.Description,
+			Keywords:    project.Technologies,
+			StartDate:   project.StartDate,
+			EndDate:     project.EndDate,
+			URL:         project.URL,
+			Roles:       roles,
+		})
+	}
+
+	for i, cert := range cv.Certifications {
+		resume.Certificates = append(resume.Certificates, JSONResumeCertificate{
+			Name:   cert.Name,
+			Date:   cert.Date,
+			Issuer: cert.Issuer,
+			URL:    cert.CredentialURL,
+		})
+		if cert.ExpiryDate != "" || cert.CredentialID != "" {
+			warn(fmt.Sprintf("certifications[%d]", i), "JSON Resume certificates have no expiry date or credential ID")
+		}
+	}
+
+	for _, award := range cv.Awards {
+		resume.Awards = append(resume.Awards, JSONResumeAward{
+			Title:   award.Title,
+			Date:    award.Date,
+			Awarder: award.Issuer,
+			Summary: award.Description,
+		})
+	}
+
+	for _, publication := range cv.Publications {
+		resume.Publications = append(resume.Publications, JSONResumePublication{
+			Name:        publication.Title,
+			Publisher:   publication.Publisher,
+			ReleaseDate: publication.Date,
+			URL:         publication.URL,
+			Summary:     publication.Description,
+		})
+	}
+
+	for _, volunteer := range cv.Volunteer {
+		resume.Volunteer = append(resume.Volunteer, JSONResumeVolunteer{
+			Organization: volunteer.Organization,
+			Position:     volunteer.Role,
+			StartDate:    volunteer.StartDate,
+			EndDate:      volunteer.EndDate,
+			Summary:      volunteer.Description,
+		})
+	}
+
+	for i := range cv.CustomSections {
+		warn(fmt.Sprintf("customSections[%d]", i), "JSON Resume has no custom sections")
+	}
+
+	// Stored CVs have an update time; CVs sent inline only a creation time
+	modified := cv.UpdatedAt
+	if modified.IsZero() {
+		modified = cv.CreatedAt
+	}
+	if !modified.IsZero() {
+		resume.Meta = &JSONResumeMetadata{LastModified: modified.Format("2006-01-02T15:04:05")}
+	}
+
+	return resume, warnings
+}
+
+// joinNonEmpty joins the non-empty, trimmed parts with sep
+func joinNonEmpty(sep string, parts ...string) string {
+	var kept []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, sep)
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"cv-generator/internal/models"
+)
+
+// hasWarning reports whether warnings has one for field
+func hasWarning(warnings []ConversionWarning, field string) bool {
+	for _, w := range warnings {
+		if w.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func TestJSONResumeImport(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		check    func(cv models.CV) bool
+		warnings []string
+	}{
+		{
+			name:  "basics",
+			input: `{"basics": {"name": "Zofia Nowak", "email": "zofia@example.com", "url": "https://zofia.dev", "location": {"city": "Kraków", "countryCode": "PL"}}}`,
+			check: func(cv models.CV) bool {
+				return cv.PersonalInfo.FullName == "Zofia Nowak" && cv.PersonalInfo.Website == "https://zofia.dev" &&
+					cv.PersonalInfo.Location == "Kraków, PL"
+			},
+		},
+		{
+			name:  "profiles",
+			input: `{"basics": {"name": "Z", "profiles": [{"network": "LinkedIn", "username": "zofia"}, {"network": "GitHub", "url": "https://github.com/zn"}, {"network": "Twitter", "username": "z"}]}}`,
+			check: func(cv models.CV) bool {
+				return cv.PersonalInfo.LinkedIn == "https://www.linkedin.com/in/zofia" && cv.PersonalInfo.GitHub == "https://github.com/zn"
+			},
+			warnings: []string{"basics.profiles[2]"},
+		},
+		{
+			name:  "pre-1.0 company",
+			input: `{"basics": {"name": "Z"}, "work": [{"company": "Acme", "position": "Engineer", "startDate": "2020-01", "location": "Remote"}]}`,
+			check: func(cv models.CV) bool {
+				return len(cv.Experience) == 1 && cv.Experience[0].Company == "Acme" && cv.Experience[0].StartDate == "2020-01"
+			},
+			warnings: []string{"work[0].location"},
+		},
+		{
+			name:  "education degree",
+			input: `{"basics": {"name": "Z"}, "education": [{"institution": "UJ", "studyType": "MSc", "area": "Physics", "courses": ["QM"]}]}`,
+			check: func(cv models.CV) bool {
+				return len(cv.Education) == 1 && cv.Education[0].Degree == "MSc, Physics"
+			},
+			warnings: []string{"education[0].courses"},
+		},
+		{
+			name:  "language fluency",
+			input: `{"basics": {"name": "Z"}, "languages": [{"language": "English", "fluency": "C1"}, {"language": "German", "fluency": "conversational-ish"}]}`,
+			check: func(cv models.CV) bool {
+				return len(cv.Languages) == 2 && cv.Languages[0].Level == models.LevelC1 && cv.Languages[1].Level == ""
+			},
+			warnings: []string{"languages[1].fluency"},
+		},
+		{
+			name:     "unsupported sections",
+			input:    `{"basics": {"name": "Z"}, "interests": [{"name": "Chess"}], "references": [{"name": "A"}]}`,
+			check:    func(cv models.CV) bool { return true },
+			warnings: []string{"interests", "references"},
+		},
+	}
+	s := NewJSONResumeService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cv, warnings, err := s.Import([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+			if !tt.check(cv) {
+				t.Errorf("Import() = %+v", cv)
+			}
+			for _, field := range tt.warnings {
+				if !hasWarning(warnings, field) {
+					t.Errorf("Import() warnings = %v, want one for %s", warnings, field)
+				}
+			}
+			if len(warnings) != len(tt.warnings) {
+				t.Errorf("Import() warnings = %v, want %d", warnings, len(tt.warnings))
+			}
+		})
+	}
+}
+
+func TestJSONResumeImportInvalid(t *testing.T) {
+	if _, _, err := NewJSONResumeService().Import([]byte(`{"basics": [}`)); err == nil {
+		t.Error("Import() error = nil, want an error for malformed JSON")
+	}
+}
+
+func TestJSONResumeRoundTrip(t *testing.T) {
+	cv := models.CV{
+		PersonalInfo: models.PersonalInfo{
+			FullName: "Zofia Nowak",
+			Email:    "zofia@example.com",
+			LinkedIn: "https://www.linkedin.com/in/zofia",
+			GitHub:   "https://github.com/zofia",
+			Website:  "https://zofia.dev",
+			Summary:  "Backend engineer",
+		},
+		Experience: []models.Experience{{
+			Company: "Acme", Position: "Engineer", StartDate: "2020-01", EndDate: "2022-06",
+			Description: "Payments", Highlights: []string{"Cut latency"},
+		}},
+		Skills:         []models.Skill{{Name: "Go", Level: "Expert"}},
+		Languages:      []models.Language{{Name: "English", Level: models.LevelC1}},
+		Certifications: []models.Certification{{Name: "CKA", Issuer: "CNCF", Date: "2021-05"}},
+		Awards:         []models.Award{{Title: "Best talk", Issuer: "GopherCon", Date: "2022"}},
+	}
+	s := NewJSONResumeService()
+	resume, _ := s.Export(cv)
+	data, err := json.Marshal(resume)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := s.Import(data)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, cv) {
+		t.Errorf("round trip = %+v\nwant %+v", got, cv)
+	}
+}
+
+func TestJSONResumeLastModified(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	updated := time.Date(2024, 6, 7, 8, 9, 10, 0, time.UTC)
+	tests := []struct {
+		name             string
+		created, updated time.Time
+		want             string
+	}{
+		{"updated", created, updated, "2024-06-07T08:09:10"},
+		{"created only", created, time.Time{}, "2024-01-02T03:04:05"},
+		{"neither", time.Time{}, time.Time{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resume, _ := NewJSONResumeService().Export(models.CV{CreatedAt: tt.created, UpdatedAt: tt.updated})
+			var got string
+			if resume.Meta != nil {
+				got = resume.Meta.LastModified
+			}
+			if got != tt.want {
+				t.Errorf("meta.lastModified = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
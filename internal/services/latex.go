@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"cv-generator/internal/models"
+)
+
+// LaTeXService renders a CV as LaTeX source for the moderncv document
+// class, to be fine-tuned and compiled by the user. All user content is
+// escaped, so the source compiles whatever the CV contains.
+type LaTeXService struct{}
+
+func NewLaTeXService() *LaTeXService {
+	return &LaTeXService{}
+}
+
+// LaTeXContentType is the media type of the generated source
+const LaTeXContentType = "application/x-tex; charset=utf-8"
+
+// latexPapers are the geometry paper names, by gofpdf page size
+var latexPapers = map[string]string{
+	"A3":     "a3paper",
+	"A4":     "a4paper",
+	"A5":     "a5paper",
+	"Letter": "letterpaper",
+	"Legal":  "legalpaper",
+}
+
+// latexLanguages are the babel languages of the supported CV languages
+var latexLanguages = map[string]string{
+	"en": "english",
+	"es": "spanish",
+}
+
+// GenerateCV renders cv as a moderncv document. The theme provides the page
+// size and the accent colors; ctx only carries logging attributes such as
+// the request ID.
+func (s *LaTeXService) GenerateCV(ctx context.Context, cv models.CV) ([]byte, error) {
+	theme, ok := GetTheme(cv.Theme)
+	if !ok {
+		return nil, fmt.Errorf("unknown theme %q", cv.Theme)
+	}
+	pageSize := theme.PageSize
+	if cv.PageSize != "" {
+		pageSize = cv.PageSize
+	}
+	paper, ok := latexPapers[pageSize]
+	if !ok {
+		return nil, fmt.Errorf("unsupported page size %q", pageSize)
+	}
+	language, ok := latexLanguages[cv.Language]
+	if !ok {
+		language = latexLanguages["en"]
+	}
+	slog.DebugContext(ctx, "generating LaTeX", "language", cv.Language, "theme", theme.Name)
+
+	w := &latexWriter{}
+	w.writePreamble(cv, theme, paper, language)
+	w.WriteString("\n\\begin{document}\n")
+	writeFlow(w, cv)
+	w.WriteString("\n\\end{document}\n")
+
+	slog.DebugContext(ctx, "LaTeX generated", "bytes", w.Len())
+	return []byte(w.String()), nil
+}
+
+// latexWriter writes the document body: sections, \cventry entries and
+// \cvlistitem lists
+type latexWriter struct {
+	strings.Builder
+}
+
+// writePreamble writes the document class, packages and the personal data
+// that \makecvtitle shows
+func (w *latexWriter) writePreamble(cv models.CV, theme Theme, paper, language string) {
+	info := cv.PersonalInfo
+	meta := metadata(cv)
+
+	w.WriteString("% Generated by CV Generator. Compile with lualatex or xelatex for full\n")
+	w.WriteString("% Unicode support; pdflatex works for Latin scripts.\n")
+	fmt.Fprintf(w, "\\documentclass[11pt,%s,sans]{moderncv}\n", paper)
+	w.WriteString("\\moderncvstyle{classic}\n")
+	w.WriteString("\\moderncvcolor{blue}\n")
+	fmt.Fprintf(w, "\\definecolor{color1}{RGB}{%s}\n", latexColor(theme.Palette.Accent))
+	fmt.Fprintf(w, "\\definecolor{color2}{RGB}{%s}\n", latexColor(theme.Palette.LightText))
+	w.WriteString("\\usepackage{iftex}\n")
+	w.WriteString("\\ifPDFTeX\n  \\usepackage[T1]{fontenc}\n  \\usepackage[utf8]{inputenc}\n\\fi\n")
+	fmt.Fprintf(w, "\\usepackage[%s]{babel}\n", language)
+	fmt.Fprintf(w, "\\usepackage[%s,scale=0.8]{geometry}\n", paper)
+	w.WriteString("\n")
+
+	// moderncv takes the first and last names apart
+	first, last, _ := strings.Cut(latexLine(info.FullName), " ")
+	fmt.Fprintf(w, "\\name{%s}{%s}\n", latexEscape(first), latexEscape(last))
+	fmt.Fprintf(w, "\\title{%s}\n", latexEscape(latexLine(meta.subject)))
+	if location := latexLine(info.Location); location != "" {
+		fmt.Fprintf(w, "\\address{%s}{}{}\n", latexEscape(location))
+	}
+	if phone := latexLine(info.Phone); phone != "" {
+		fmt.Fprintf(w, "\\phone[mobile]{%s}\n", latexEscape(phone))
+	}
+	if email := latexLine(info.Email); email != "" {
+		fmt.Fprintf(w, "\\email{%s}\n", latexEscape(email))
+	}
+	// moderncv adds the scheme when it builds the link
+	if website := latexLine(info.Website); website != "" {
+		fmt.Fprintf(w, "\\homepage{%s}\n", latexEscape(strings.TrimPrefix(strings.TrimPrefix(website, "https://"), "http://")))
+	}
+	w.writeSocial("linkedin", latexLine(info.LinkedIn), "linkedin.com", "/in/")
+	w.writeSocial("github", latexLine(info.GitHub), "github.com", "/")
+}
+
+// writeSocial writes a \social account. moderncv expects the user name, so
+// it is taken from the path after prefix of a profile URL on host; other
+// addresses are shown as is.
+func (w *latexWriter) writeSocial(network, profile, host, prefix string) {
+	if profile == "" {
+		return
+	}
+	link := webLink(profile)
+	if u, err := url.Parse(link); err == nil && (u.Hostname() == host || strings.HasSuffix(u.Hostname(), "."+host)) &&
+		strings.HasPrefix(u.Path, prefix) {
+		if user := strings.Trim(strings.TrimPrefix(u.Path, prefix), "/"); user != "" && !strings.Contains(user, "/") {
+			fmt.Fprintf(w, "\\social[%s]{%s}\n", network, latexEscape(user))
+			return
+		}
+	}
+	fmt.Fprintf(w, "\\extrainfo{%s}\n", latexSpan(mdSpan{text: profile, link: link}))
+}
+
+func (w *latexWriter) writeHeader(name string, contact []mdSpan) {
+	w.WriteString("\\makecvtitle\n")
+}
+
+func (w *latexWriter) writeSection(title string) {
+	fmt.Fprintf(w, "\n\\section{%s}\n", latexEscape(latexLine(title)))
+}
+
+// writeEntries writes each entry as a \cventry: the dates in the margin,
+// the position or degree in bold, the company or institution in italics
+// after it, and the details and description below
+func (w *latexWriter) writeEntries(entries []entry) {
+	for _, e := range entries {
+		var body []string
+		for _, line := range e.details {
+			if line.text = latexLine(line.text); line.text != "" {
+				body = append(body, latexSpan(line))
+			}
+		}
+		text := strings.Join(body, "\\newline{}\n")
+		if blocks := e.blocks(); len(blocks) > 0 {
+			// A list starts on a line of its own
+			if text != "" && !blocks[0].bullet {
+				text += "\\newline{}"
+			}
+			text += "\n" + latexBlocks(blocks)
+		}
+
+		fmt.Fprintf(w, "\\cventry{%s}{%s}{%s}{}{}{%s}\n", latexEscape(latexLine(e.dates)),
+			latexEscape(latexLine(e.role)), latexEscape(latexLine(e.organization)), strings.TrimPrefix(text, "\n"))
+	}
+}
+
+func (w *latexWriter) writeText(text string) {
+	fmt.Fprintf(w, "\\cvitem{}{%s}\n", latexEscape(text))
+}
+
+func (w *latexWriter) writeList(items []string) {
+	for _, item := range items {
+		fmt.Fprintf(w, "\\cvlistitem{%s}\n", latexEscape(latexLine(item)))
+	}
+}
+
+// latexBlocks returns paragraphs separated by line breaks and consecutive
+// bullets as an itemize list
+func latexBlocks(blocks []mdBlock) string {
+	var b strings.Builder
+	for i, block := range blocks {
+		prev := i > 0 && blocks[i-1].bullet
+		switch {
+		case i == 0:
+		case prev && !block.bullet:
+			// The list already ends the line
+			b.WriteString("\n\\end{itemize}\n")
+		case !prev && !block.bullet:
+			b.WriteString("\\newline{}\n")
+		default:
+			b.WriteString("\n")
+		}
+		if block.bullet {
+			if !prev {
+				b.WriteString("\\begin{itemize}\n")
+			}
+			// The empty group keeps a leading "[" from being read as the
+			// optional argument of \item
+			b.WriteString("\\item{} ")
+		}
+		for _, span := range block.spans {
+			b.WriteString(latexSpan(span))
+		}
+	}
+	if n := len(blocks); n > 0 && blocks[n-1].bullet {
+		b.WriteString("\n\\end{itemize}")
+	}
+	return b.String()
+}
+
+// latexSpan returns a span as escaped LaTeX with its emphasis and link
+func latexSpan(span mdSpan) string {
+	text := latexEscape(span.text)
+	if span.italic {
+		text = "\\emph{" + text + "}"
+	}
+	if span.bold {
+		text = "\\textbf{" + text + "}"
+	}
+	if span.link != "" {
+		text = "\\href{" + latexURL(span.link) + "}{" + text + "}"
+	}
+	return text
+}
+
+// latexReplacer escapes the LaTeX special characters. Characters that
+// render as other glyphs in some font encodings, or that babel turns into
+// shorthands, are written as commands.
+var latexReplacer = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`$`, `\$`,
+	`&`, `\&`,
+	`#`, `\#`,
+	`%`, `\%`,
+	`_`, `\_`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+	`<`, `\textless{}`,
+	`>`, `\textgreater{}`,
+	`|`, `\textbar{}`,
+	`"`, `\textquotedbl{}`,
+)
+
+// latexEscape escapes user text for use in LaTeX source
+func latexEscape(text string) string {
+	return latexReplacer.Replace(text)
+}
+
+// latexLine cleans text for a command argument. The argument is kept on
+// one line: a blank line would end the paragraph before the command is
+// complete, which LaTeX reports as "Paragraph ended before ... was complete".
+func latexLine(text string) string {
+	return oneLine(cleanText(text))
+}
+
+// latexURL escapes a URL for \href. Only "#" and "%" need a backslash
+// there; braces and backslashes are percent-encoded.
+func latexURL(link string) string {
+	return strings.NewReplacer(
+		`#`, `\#`,
+		`%`, `\%`,
+		`{`, `\%7B`,
+		`}`, `\%7D`,
+		`\`, `\%5C`,
+	).Replace(link)
+}
+
+// latexColor returns c as the "R,G,B" value of \definecolor
+func latexColor(c Color) string {
+	return fmt.Sprintf("%d,%d,%d", c.R, c.G, c.B)
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"cv-generator/internal/models"
+)
+
+func TestLaTeXEscape(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"plain text", "plain text"},
+		{"R&D 100% #1", `R\&D 100\% \#1`},
+		{"$5_000", `\$5\_000`},
+		{`C:\{x}`, `C:\textbackslash{}\{x\}`},
+		{"~user ^2", `\textasciitilde{}user \textasciicircum{}2`},
+		{`<a href="x">|`, `\textless{}a href=\textquotedbl{}x\textquotedbl{}\textgreater{}\textbar{}`},
+		{"Zażółć gęślą", "Zażółć gęślą"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := latexEscape(tt.input); got != tt.want {
+				t.Errorf("latexEscape(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLaTeXURL(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"https://go.dev/doc", "https://go.dev/doc"},
+		{"https://x.dev/a#b", `https://x.dev/a\#b`},
+		{"https://x.dev/a%20b", `https://x.dev/a\%20b`},
+		{`https://x.dev/{a}\b`, `https://x.dev/\%7Ba\%7D\%5Cb`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := latexURL(tt.input); got != tt.want {
+				t.Errorf("latexURL(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLaTeXSpan(t *testing.T) {
+	tests := []struct {
+		name string
+		span mdSpan
+		want string
+	}{
+		{"plain", mdSpan{text: "a_b"}, `a\_b`},
+		{"bold italic", mdSpan{text: "x", bold: true, italic: true}, `\textbf{\emph{x}}`},
+		{"link", mdSpan{text: "50%", link: "https://x.dev/#top"}, `\href{https://x.dev/\#top}{50\%}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := latexSpan(tt.span); got != tt.want {
+				t.Errorf("latexSpan(%+v) = %q, want %q", tt.span, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLaTeXBlankLines(t *testing.T) {
+	cv := models.CV{
+		PersonalInfo: models.PersonalInfo{FullName: "Zofia\n\nNowak", Email: "zofia@example.com", Location: "Kraków\n\nPoland"},
+		Experience: []models.Experience{{
+			Company: "Acme\n\n& Co", Position: "Senior\n\n  Engineer", StartDate: "2020-01",
+			Description: "First paragraph\n\nSecond paragraph",
+		}},
+		Certifications: []models.Certification{{Name: "CKA", CredentialID: "ABC\n\n123"}},
+		Skills:         []models.Skill{{Name: "Go\n\nSQL"}},
+		CustomSections: []models.CustomSection{{Title: "Open\n\nsource", Content: "cvgen\n\nand more"}},
+	}
+	out, err := NewLaTeXService().GenerateCV(context.Background(), cv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"name", `\name{Zofia}{Nowak}`},
+		{"address", `\address{Kraków Poland}{}{}`},
+		{"cventry arguments", `\cventry{2020-01 - Present}{Senior Engineer}{Acme \& Co}{}{}{First paragraph\newline{}`},
+		{"detail line", `{Credential ID: ABC 123}`},
+		{"list item", `\cvlistitem{Go SQL}`},
+		{"section title", `\section{Open source}`},
+		{"text", `\cvitem{}{cvgen and more}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(string(out), tt.want) {
+				t.Errorf("GenerateCV() = %s\nwant it to contain %s", out, tt.want)
+			}
+		})
+	}
+
+	// Paragraphs only end between commands, never inside an argument
+	for _, paragraph := range strings.Split(string(out), "\n\n") {
+		unescaped := strings.NewReplacer(`\{`, "", `\}`, "").Replace(paragraph)
+		if strings.Count(unescaped, "{") != strings.Count(unescaped, "}") {
+			t.Errorf("unbalanced braces in paragraph %q", paragraph)
+		}
+	}
+}
+
+func TestLaTeXGenerateCV(t *testing.T) {
+	cv := models.CV{
+		PersonalInfo: models.PersonalInfo{
+			FullName: "Zofia Nowak",
+			Email:    "zofia@example.com",
+			Website:  "zofia.dev",
+			LinkedIn: "www.linkedin.com/in/zofia/",
+			GitHub:   "https://gitlab.com/zofia",
+		},
+		Experience: []models.Experience{{
+			Company: "Acme & Co", Position: "Engineer", StartDate: "2020-01", EndDate: "2022-06",
+			Description: "Payments", Highlights: []string{"Cut latency by 50%"},
+		}},
+		Education: []models.Education{{Institution: "UJ", Degree: "MSc", StartDate: "2015", EndDate: "2020"}},
+		Projects:  []models.Project{{Name: "cvgen", URL: "github.com/zofia/cvgen"}},
+	}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"homepage without scheme", `\homepage{zofia.dev}`},
+		{"social from a bare host", `\social[linkedin]{zofia}`},
+		{"other host as extra info", `\extrainfo{\href{https://gitlab.com/zofia}{https://gitlab.com/zofia}}`},
+		{"experience slots", `\cventry{2020-01 - 2022-06}{Engineer}{Acme \& Co}{}{}{Payments` + "\n" + `\begin{itemize}`},
+		{"highlight", `\item{} Cut latency by 50\%`},
+		{"education slots", `\cventry{2015 - 2020}{MSc}{UJ}{}{}{}`},
+		{"project link", `\cventry{}{cvgen}{}{}{}{\href{https://github.com/zofia/cvgen}{github.com/zofia/cvgen}}`},
+	}
+	out, err := NewLaTeXService().GenerateCV(context.Background(), cv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(string(out), tt.want) {
+				t.Errorf("GenerateCV() = %s\nwant it to contain %s", out, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"cv-generator/internal/models"
+)
+
+// LinkedInService reads the data export that LinkedIn members download from
+// their settings ("Get a copy of your data"): a ZIP archive with one CSV
+// file per kind of data. The archive is read in memory and only the files
+// below are opened.
+type LinkedInService struct{}
+
+func NewLinkedInService() *LinkedInService {
+	return &LinkedInService{}
+}
+
+// LinkedIn export files that Import reads
+const (
+	linkedInProfile        = "Profile.csv"
+	linkedInEmails         = "Email Addresses.csv"
+	linkedInPhones         = "PhoneNumbers.csv"
+	linkedInPositions      = "Positions.csv"
+	linkedInEducation      = "Education.csv"
+	linkedInSkills         = "Skills.csv"
+	linkedInLanguages      = "Languages.csv"
+	linkedInCertifications = "Certifications.csv"
+	linkedInProjects       = "Projects.csv"
+)
+
+// maxLinkedInFileSize bounds the uncompressed size of each CSV file, so a
+// crafted archive cannot exhaust memory
+const maxLinkedInFileSize = 4 << 20
+
+// linkedInProficiencies maps LinkedIn language proficiencies to the closest
+// CEFR level
+var linkedInProficiencies = map[string]string{
+	"native or bilingual proficiency":  models.LevelNative,
+	"full professional proficiency":    models.LevelC2,
+	"professional working proficiency": models.LevelC1,
+	"limited working proficiency":      models.LevelB1,
+	"elementary proficiency":           models.LevelA2,
+}
+
+// linkedInDateLayouts are the date formats found in LinkedIn exports, with
+// the CV format of the same precision
+var linkedInDateLayouts = []struct{ layout, format string }{
+	{"Jan 2006", "2006-01"},
+	{"January 2006", "2006-01"},
+	{"2006", "2006"},
+	{"2006-01", "2006-01"},
+	{"2006-01-02", "2006-01-02"},
+	{"Jan 2, 2006", "2006-01-02"},
+	{"01/02/06", "2006-01-02"},
+}
+
+// linkedInTable is a CSV file of the export. Columns read through get are
+// remembered, so the others can be reported as not imported.
+type linkedInTable struct {
+	name    string
+	columns []string
+	rows    []map[string]string
+	used    map[string]bool
+}
+
+// get returns the trimmed value of column in row and marks the column as
+// imported
+func (t *linkedInTable) get(row map[string]string, column string) string {
+	t.used[column] = true
+	return strings.TrimSpace(row[column])
+}
+
+// ignore marks columns as intentionally left out, such as export metadata
+func (t *linkedInTable) ignore(columns ...string) {
+	for _, column := range columns {
+		t.used[column] = true
+	}
+}
+
+// Import converts a LinkedIn data export into a models.CV. Columns that
+// hold data but have no models.CV counterpart are listed in the returned
+// warnings, one per column.
+func (s *LinkedInService) Import(data []byte) (models.CV, []ConversionWarning, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return models.CV{}, nil, fmt.Errorf("invalid LinkedIn export: %w", err)
+	}
+	// Files are matched by name wherever they are in the archive
+	files := make(map[string]*zip.File)
+	for _, f := range archive.File {
+		files[strings.ToLower(path.Base(f.Name))] = f
+	}
+
+	// table reads a file, or returns nil if it is missing or unreadable;
+	// the first read error is returned once the import is done
+	var tables []*linkedInTable
+	var readErr error
+	table := func(name, key string) *linkedInTable {
+		f, ok := files[strings.ToLower(name)]
+		if !ok || readErr != nil {
+			return nil
+		}
+		t, err := readLinkedInTable(f, name, key)
+		if err != nil {
+			readErr = err
+			return nil
+		}
+		tables = append(tables, t)
+		return t
+	}
+
+	var cv models.CV
+	var warnings []ConversionWarning
+	warn := func(field, format string, args ...interface{}) {
+		warnings = append(warnings, ConversionWarning{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	date := func(t *linkedInTable, i int, row map[string]string, column string) string {
+		value := t.get(row, column)
+		converted, ok := linkedInDate(value)
+		if !ok {
+			warn(fmt.Sprintf("%s[%d]/%s", t.name, i, column), "date %q is not recognized", value)
+		}
+		return converted
+	}
+
+	// Profile
+	profile := table(linkedInProfile, "First Name")
+	if profile != nil && len(profile.rows) > 0 {
+		row := profile.rows[0]
+		cv.PersonalInfo.FullName = joinNonEmpty(" ", profile.get(row, "First Name"), profile.get(row, "Last Name"))
+		cv.PersonalInfo.Summary = profile.get(row, "Summary")
+		cv.PersonalInfo.Location = profile.get(row, "Geo Location")
+		// Websites are written as "[PERSONAL:https://a.dev,COMPANY:https://b.com]"
+		websites := strings.Trim(profile.get(row, "Websites"), "[]")
+		for _, website := range strings.Split(websites, ",") {
+			if kind, link, ok := strings.Cut(website, ":"); ok && !strings.HasPrefix(link, "//") && !strings.Contains(kind, ".") {
+				website = link
+			}
+			if website = strings.TrimSpace(website); website != "" && !addProfileLink(&cv.PersonalInfo, website) {
+				warn(profile.name+"/Websites", "website %s not imported, only one personal website is supported", website)
+			}
+		}
+	}
+
+	// Contact details: the primary email address and the first phone number
+	emails := table(linkedInEmails, "Email Address")
+	if emails != nil {
+		emails.ignore("Confirmed", "Updated On")
+		for _, row := range emails.rows {
+			email, primary := emails.get(row, "Email Address"), emails.get(row, "Primary")
+			if cv.PersonalInfo.Email == "" || strings.EqualFold(primary, "yes") {
+				cv.PersonalInfo.Email = email
+			}
+		}
+	}
+	phones := table(linkedInPhones, "Number")
+	if phones != nil {
+		phones.ignore("Type")
+		for i, row := range phones.rows {
+			number := joinNonEmpty(" ext. ", phones.get(row, "Number"), phones.get(row, "Extension"))
+			if i == 0 {
+				cv.PersonalInfo.Phone = number
+			} else {
+				warn(fmt.Sprintf("%s[%d]", phones.name, i), "only one phone number is supported")
+			}
+		}
+	}
+
+	// Positions
+	positions := table(linkedInPositions, "Company Name")
+	if positions != nil {
+		for i, row := range positions.rows {
+			cv.Experience = append(cv.Experience, models.Experience{
+				Company:     positions.get(row, "Company Name"),
+				Position:    positions.get(row, "Title"),
+				StartDate:   date(positions, i, row, "Started On"),
+				EndDate:     date(positions, i, row, "Finished On"),
+				Description: positions.get(row, "Description"),
+			})
+		}
+	}
+
+	// Education. Activities follow the notes in the description.
+	education := table(linkedInEducation, "School Name")
+	if education != nil {
+		for i, row := range education.rows {
+			cv.Education = append(cv.Education, models.Education{
+				Institution: education.get(row, "School Name"),
+				Degree:      education.get(row, "Degree Name"),
+				StartDate:   date(education, i, row, "Start Date"),
+				EndDate:     date(education, i, row, "End Date"),
+				Description: joinNonEmpty("\n\n", education.get(row, "Notes"), education.get(row, "Activities")),
+			})
+		}
+	}
+
+	// Skills have no level on LinkedIn
+	skills := table(linkedInSkills, "Name")
+	if skills != nil {
+		for _, row := range skills.rows {
+			if name := skills.get(row, "Name"); name != "" {
+				cv.Skills = append(cv.Skills, models.Skill{Name: name})
+			}
+		}
+	}
+
+	// Languages
+	languages := table(linkedInLanguages, "Name")
+	if languages != nil {
+		for i, row := range languages.rows {
+			language := models.Language{Name: languages.get(row, "Name")}
+			if proficiency := languages.get(row, "Proficiency"); proficiency != "" {
+				level, ok := linkedInProficiencies[strings.ToLower(proficiency)]
+				if !ok {
+					level, ok = models.ParseLanguageLevel(proficiency)
+				}
+				field := fmt.Sprintf("%s[%d]/Proficiency", languages.name, i)
+				switch {
+				case !ok:
+					warn(field, "proficiency %q does not match a CEFR level", proficiency)
+				case level != models.LevelNative:
+					warn(field, "proficiency %q approximated as CEFR %s", proficiency, level)
+				}
+				language.Level = level
+			}
+			cv.Languages = append(cv.Languages, language)
+		}
+	}
+
+	// Certifications
+	certifications := table(linkedInCertifications, "Name")
+	if certifications != nil {
+		for i, row := range certifications.rows {
+			cv.Certifications = append(cv.Certifications, models.Certification{
+				Name:          certifications.get(row, "Name"),
+				Issuer:        certifications.get(row, "Authority"),
+				Date:          date(certifications, i, row, "Started On"),
+				ExpiryDate:    date(certifications, i, row, "Finished On"),
+				CredentialID:  certifications.get(row, "License Number"),
+				CredentialURL: certifications.get(row, "Url"),
+			})
+		}
+	}
+
+	// Projects
+	projects := table(linkedInProjects, "Title")
+	if projects != nil {
+		for i, row := range projects.rows {
+			cv.Projects = append(cv.Projects, models.Project{
+				Name:        projects.get(row, "Title"),
+				URL:         projects.get(row, "Url"),
+				StartDate:   date(projects, i, row, "Started On"),
+				EndDate:     date(projects, i, row, "Finished On"),
+				Description: projects.get(row, "Description"),
+			})
+		}
+	}
+
+	if readErr != nil {
+		return models.CV{}, nil, readErr
+	}
+	if len(tables) == 0 {
+		return models.CV{}, nil, fmt.Errorf("invalid LinkedIn export: the archive has none of %s", strings.Join([]string{
+			linkedInProfile, linkedInPositions, linkedInEducation, linkedInSkills, linkedInLanguages,
+		}, ", "))
+	}
+
+	// Columns with data that was not imported
+	for _, t := range tables {
+		for _, column := range t.columns {
+			if t.used[column] {
+				continue
+			}
+			values := 0
+			for _, row := range t.rows {
+				if strings.TrimSpace(row[column]) != "" {
+					values++
+				}
+			}
+			if values > 0 {
+				warn(t.name+"/"+column, "column not imported, %d value(s) skipped", values)
+			}
+		}
+	}
+
+	return cv, warnings, nil
+}
+
+// readLinkedInTable reads a CSV file of the export. Some files start with
+// notes before the header, so the header is the first record that has the
+// key column.
+func readLinkedInTable(f *zip.File, name, key string) (*linkedInTable, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("invalid LinkedIn export: %s: %w", name, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxLinkedInFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LinkedIn export: %s: %w", name, err)
+	}
+	if len(data) > maxLinkedInFileSize {
+		return nil, fmt.Errorf("invalid LinkedIn export: %s is larger than %d MB", name, maxLinkedInFileSize>>20)
+	}
+
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid LinkedIn export: %s: %w", name, err)
+	}
+
+	t := &linkedInTable{name: name, used: make(map[string]bool)}
+	for _, record := range records {
+		if t.columns == nil {
+			for _, column := range record {
+				if strings.TrimSpace(column) == key {
+					t.columns = record
+				}
+			}
+			for j := range t.columns {
+				t.columns[j] = strings.TrimSpace(t.columns[j])
+			}
+			continue
+		}
+		row := make(map[string]string, len(t.columns))
+		for j, value := range record {
+			if j < len(t.columns) {
+				row[t.columns[j]] = value
+			}
+		}
+		t.rows = append(t.rows, row)
+	}
+	if t.columns == nil {
+		return nil, fmt.Errorf("invalid LinkedIn export: %s has no %q column", name, key)
+	}
+	return t, nil
+}
+
+// linkedInDate converts a LinkedIn date such as "Mar 2019" to the CV
+// format. Empty values are valid and stay empty.
+func linkedInDate(value string) (string, bool) {
+	if value == "" {
+		return "", true
+	}
+	for _, l := range linkedInDateLayouts {
+		if t, err := time.Parse(l.layout, value); err == nil {
+			return t.Format(l.format), true
+		}
+	}
+	return "", false
+}
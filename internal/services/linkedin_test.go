@@ -0,0 +1,153 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cv-generator/internal/models"
+)
+
+// linkedInExport returns a ZIP archive with the given files, the way
+// LinkedIn builds its data export
+func linkedInExport(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestLinkedInImport(t *testing.T) {
+	files := map[string]string{
+		"Profile.csv": "\xef\xbb\xbfFirst Name,Last Name,Headline,Summary,Geo Location,Websites\n" +
+			`Zofia,Nowak,Backend engineer,Go and payments,"Kraków, Poland","[PERSONAL:https://zofia.dev,COMPANY:https://acme.com]"` + "\n",
+		"Email Addresses.csv": "Email Address,Confirmed,Primary,Updated On\n" +
+			"old@example.com,Yes,No,1/2/20\nzofia@example.com,Yes,Yes,1/2/21\n",
+		"PhoneNumbers.csv": "Extension,Number,Type\n,+48 600 123 456,Mobile\n,+48 12 000,Work\n",
+		"Positions.csv": "Company Name,Title,Description,Location,Started On,Finished On\n" +
+			"Acme,Engineer,Payments,Kraków,Mar 2019,\nInitech,Intern,,,2017,sometime\n",
+		"Education.csv": "School Name,Start Date,End Date,Notes,Degree Name,Activities\n" +
+			"UJ,2012,2017,Physics,MSc,Chess club\n",
+		"Skills.csv":    "Name\nGo\n\nSQL\n",
+		"Languages.csv": "Name,Proficiency\nPolish,Native or bilingual proficiency\nEnglish,Full professional proficiency\nKlingon,Fluent-ish\n",
+		"Certifications.csv": "Name,Url,Authority,Started On,Finished On,License Number\n" +
+			"CKA,https://cncf.io/c/1,CNCF,May 2021,May 2024,ABC-123\n",
+		"Projects.csv":    "Title,Description,Url,Started On,Finished On\ncvgen,CV generator,https://github.com/zofia/cvgen,Jan 2023,\n",
+		"Connections.csv": "Notes:\nNot read\n",
+	}
+	cv, warnings, err := NewLinkedInService().Import(linkedInExport(t, files))
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	want := models.CV{
+		PersonalInfo: models.PersonalInfo{
+			FullName: "Zofia Nowak",
+			Email:    "zofia@example.com",
+			Phone:    "+48 600 123 456",
+			Location: "Kraków, Poland",
+			Website:  "https://zofia.dev",
+			Summary:  "Go and payments",
+		},
+		Experience: []models.Experience{
+			{Company: "Acme", Position: "Engineer", StartDate: "2019-03", Description: "Payments"},
+			{Company: "Initech", Position: "Intern", StartDate: "2017"},
+		},
+		Education: []models.Education{
+			{Institution: "UJ", Degree: "MSc", StartDate: "2012", EndDate: "2017", Description: "Physics\n\nChess club"},
+		},
+		Skills: []models.Skill{{Name: "Go"}, {Name: "SQL"}},
+		Languages: []models.Language{
+			{Name: "Polish", Level: models.LevelNative},
+			{Name: "English", Level: models.LevelC2},
+			{Name: "Klingon"},
+		},
+		Certifications: []models.Certification{{
+			Name: "CKA", Issuer: "CNCF", Date: "2021-05", ExpiryDate: "2024-05",
+			CredentialID: "ABC-123", CredentialURL: "https://cncf.io/c/1",
+		}},
+		Projects: []models.Project{{Name: "cvgen", URL: "https://github.com/zofia/cvgen", StartDate: "2023-01", Description: "CV generator"}},
+	}
+	if !reflect.DeepEqual(cv, want) {
+		t.Errorf("Import() = %+v\nwant %+v", cv, want)
+	}
+
+	for _, field := range []string{
+		"Profile.csv/Headline",
+		"Profile.csv/Websites",
+		"PhoneNumbers.csv[1]",
+		"Positions.csv/Location",
+		"Positions.csv[1]/Finished On",
+		"Languages.csv[1]/Proficiency",
+		"Languages.csv[2]/Proficiency",
+	} {
+		if !hasWarning(warnings, field) {
+			t.Errorf("Import() warnings = %v, want one for %s", warnings, field)
+		}
+	}
+	if hasWarning(warnings, "Languages.csv[0]/Proficiency") {
+		t.Errorf("Import() warnings = %v, want none for a native language", warnings)
+	}
+}
+
+func TestLinkedInImportInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data func(t *testing.T) []byte
+		want string
+	}{
+		{"not a zip", func(t *testing.T) []byte { return []byte("First Name\nZofia\n") }, "invalid LinkedIn export"},
+		{"no known files", func(t *testing.T) []byte {
+			return linkedInExport(t, map[string]string{"Connections.csv": "First Name\n"})
+		}, "the archive has none of"},
+		{"missing key column", func(t *testing.T) []byte {
+			return linkedInExport(t, map[string]string{"Positions.csv": "Title\nEngineer\n"})
+		}, `Positions.csv has no "Company Name" column`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := NewLinkedInService().Import(tt.data(t))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Import() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkedInDate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"", "", true},
+		{"Mar 2019", "2019-03", true},
+		{"September 2020", "2020-09", true},
+		{"2017", "2017", true},
+		{"Jan 5, 2021", "2021-01-05", true},
+		{"01/05/21", "2021-01-05", true},
+		{"sometime", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := linkedInDate(tt.input)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("linkedInDate(%q) = %q, %v, want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"html/template"
+	"net/url"
+	"strings"
+)
+
+// Descriptions support a small, safe Markdown subset: paragraphs separated
+// by blank lines, "-" or "*" bullets, **bold**, *italic* and [links](url).
+// Anything else, including raw HTML, is kept as literal text.
+
+// mdSpan is a run of text with a single style
+type mdSpan struct {
+	text   string
+	bold   bool
+	italic bool
+	link   string // only set for http, https and mailto URLs
+}
+
+// mdBlock is a paragraph or a bullet point
+type mdBlock struct {
+	bullet bool
+	spans  []mdSpan
+}
+
+// parseMarkdown splits text into paragraphs and bullets. Consecutive lines
+// are joined into one paragraph; a line that does not start a new bullet
+// continues the previous one.
+func parseMarkdown(text string) []mdBlock {
+	var blocks []mdBlock
+	var current []string
+	bullet := false
+
+	flush := func() {
+		if len(current) > 0 {
+			blocks = append(blocks, mdBlock{bullet: bullet, spans: parseInline(strings.Join(current, " "))})
+		}
+		current, bullet = nil, false
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") || strings.HasPrefix(line, "• "):
+			flush()
+			bullet = true
+			_, item, _ := strings.Cut(line, " ")
+			current = []string{strings.TrimSpace(item)}
+		default:
+			current = append(current, line)
+		}
+	}
+	flush()
+
+	return blocks
+}
+
+// parseInline splits one line of text into styled spans. A marker only
+// opens a style when it is followed by text and closed later on, so stray
+// asterisks such as "5 * 3" are printed as is.
+func parseInline(text string) []mdSpan {
+	var spans []mdSpan
+	var buf strings.Builder
+	bold, italic := false, false
+
+	flush := func() {
+		if buf.Len() > 0 {
+			spans = append(spans, mdSpan{text: buf.String(), bold: bold, italic: italic})
+			buf.Reset()
+		}
+	}
+	opens := func(rest, marker string) bool {
+		return rest != "" && rest[0] != ' ' && strings.Contains(rest, marker)
+	}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && strings.IndexByte(`\*[]()`, rest[1]) >= 0:
+			buf.WriteByte(rest[1])
+			i += 2
+		case strings.HasPrefix(rest, "**") && (bold || opens(rest[2:], "**")):
+			flush()
+			bold = !bold
+			i += 2
+		case rest[0] == '*' && !strings.HasPrefix(rest, "**") && (italic || opens(rest[1:], "*")):
+			flush()
+			italic = !italic
+			i++
+		case rest[0] == '[':
+			label, target, n, ok := parseLink(rest)
+			if !ok {
+				buf.WriteByte('[')
+				i++
+				continue
+			}
+			flush()
+			spans = append(spans, mdSpan{text: label, bold: bold, italic: italic, link: safeLink(target)})
+			i += n
+		default:
+			buf.WriteByte(rest[0])
+			i++
+		}
+	}
+	flush()
+
+	return spans
+}
+
+// parseLink parses "[label](target)" at the start of text and returns the
+// number of bytes consumed
+func parseLink(text string) (label, target string, n int, ok bool) {
+	end := strings.Index(text, "](")
+	if end < 1 || strings.ContainsAny(text[1:end], "[]") {
+		return "", "", 0, false
+	}
+	// The target ends at the matching parenthesis, so URLs such as
+	// Wikipedia's "Go_(programming_language)" stay intact
+	depth := 0
+	for i := end + 2; i < len(text); i++ {
+		switch text[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return text[1:end], strings.TrimSpace(text[end+2 : i]), i + 1, true
+			}
+			depth--
+		}
+	}
+	return "", "", 0, false
+}
+
+// safeLink returns target if it is an absolute http, https or mailto URL,
+// and "" otherwise so the label is rendered without a link
+func safeLink(target string) string {
+	u, err := url.Parse(target)
+	if err != nil {
+		return ""
+	}
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return ""
+		}
+		return target
+	case "mailto":
+		return target
+	}
+	return ""
+}
+
+// markdownHTML renders text as escaped HTML for the CV template. Bullets are
+// grouped into lists.
+func markdownHTML(text string, highlights []string) template.HTML {
+	blocks := parseMarkdown(text)
+	for _, highlight := range highlights {
+		blocks = append(blocks, mdBlock{bullet: true, spans: parseInline(strings.TrimSpace(highlight))})
+	}
+
+	var b strings.Builder
+	inList := false
+	for _, block := range blocks {
+		if block.bullet != inList {
+			if inList {
+				b.WriteString("</ul>")
+			} else {
+				b.WriteString("<ul>")
+			}
+			inList = block.bullet
+		}
+		if block.bullet {
+			b.WriteString("<li>")
+		} else {
+			b.WriteString("<p>")
+		}
+		for _, span := range block.spans {
+			writeSpanHTML(&b, span)
+		}
+		if block.bullet {
+			b.WriteString("</li>")
+		} else {
+			b.WriteString("</p>")
+		}
+	}
+	if inList {
+		b.WriteString("</ul>")
+	}
+
+	return template.HTML(b.String())
+}
+
+// descriptionMarkdown writes blocks as description Markdown, the reverse of
+// parseMarkdown
+func descriptionMarkdown(blocks []mdBlock) string {
+	var b strings.Builder
+	for i, block := range blocks {
+		switch {
+		case i == 0:
+		case block.bullet && blocks[i-1].bullet:
+			b.WriteString("\n")
+		default:
+			b.WriteString("\n\n")
+		}
+		if block.bullet {
+			b.WriteString("- ")
+		}
+		for _, span := range block.spans {
+			b.WriteString(markdownSpan(span, descriptionEscape))
+		}
+	}
+	return b.String()
+}
+
+// descriptionEscape escapes the characters that parseInline reads as markup
+func descriptionEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`, `[`, `\[`, `]`, `\]`).Replace(text)
+}
+
+func writeSpanHTML(b *strings.Builder, span mdSpan) {
+	var open, closing []string
+	if span.link != "" {
+		open = append(open, `<a href="`+template.HTMLEscapeString(span.link)+`">`)
+		closing = append([]string{"</a>"}, closing...)
+	}
+	if span.bold {
+		open = append(open, "<strong>")
+		closing = append([]string{"</strong>"}, closing...)
+	}
+	if span.italic {
+		open = append(open, "<em>")
+		closing = append([]string{"</em>"}, closing...)
+	}
+	b.WriteString(strings.Join(open, ""))
+	b.WriteString(template.HTMLEscapeString(span.text))
+	b.WriteString(strings.Join(closing, ""))
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLink(t *testing.T) {
+	tests := []struct {
+		input  string
+		label  string
+		target string
+		n      int
+		ok     bool
+	}{
+		{"[Go](https://go.dev) rest", "Go", "https://go.dev", 20, true},
+		{"[Go](https://en.wikipedia.org/wiki/Go_(programming_language))", "Go", "https://en.wikipedia.org/wiki/Go_(programming_language)", 61, true},
+		{"[a](https://x.dev/(b(c))) tail", "a", "https://x.dev/(b(c))", 25, true},
+		{"[a]( https://x.dev )", "a", "https://x.dev", 20, true},
+		{"[a](https://x.dev/(open", "", "", 0, false},
+		{"[a [b]](https://x.dev)", "", "", 0, false},
+		{"[a] (https://x.dev)", "", "", 0, false},
+		{"[no link]", "", "", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			label, target, n, ok := parseLink(tt.input)
+			if label != tt.label || target != tt.target || n != tt.n || ok != tt.ok {
+				t.Errorf("parseLink(%q) = %q, %q, %d, %v, want %q, %q, %d, %v",
+					tt.input, label, target, n, ok, tt.label, tt.target, tt.n, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseInline(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []mdSpan
+	}{
+		{"plain text", []mdSpan{{text: "plain text"}}},
+		{"**bold** and *italic*", []mdSpan{{text: "bold", bold: true}, {text: " and "}, {text: "italic", italic: true}}},
+		{"5 * 3 = 15", []mdSpan{{text: "5 * 3 = 15"}}},
+		{"a * b * c", []mdSpan{{text: "a * b * c"}}},
+		{"*unclosed", []mdSpan{{text: "*unclosed"}}},
+		{"** not bold**", []mdSpan{{text: "** not bold**"}}},
+		{"2*x*y", []mdSpan{{text: "2"}, {text: "x", italic: true}, {text: "y"}}},
+		{`\*escaped\*`, []mdSpan{{text: "*escaped*"}}},
+		{"**[Go](https://go.dev)**", []mdSpan{{text: "Go", bold: true, link: "https://go.dev"}}},
+		{"[x](javascript:alert(1))", []mdSpan{{text: "x"}}},
+		{"see [mail](mailto:a@b.dev)", []mdSpan{{text: "see "}, {text: "mail", link: "mailto:a@b.dev"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := parseInline(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseInline(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []mdBlock
+	}{
+		{"joined lines", "one\ntwo", []mdBlock{{spans: []mdSpan{{text: "one two"}}}}},
+		{"paragraphs", "one\r\n\r\ntwo", []mdBlock{{spans: []mdSpan{{text: "one"}}}, {spans: []mdSpan{{text: "two"}}}}},
+		{"bullets", "- a\n* b\ncontinued", []mdBlock{
+			{bullet: true, spans: []mdSpan{{text: "a"}}},
+			{bullet: true, spans: []mdSpan{{text: "b continued"}}},
+		}},
+		{"asterisk without space", "*a* b", []mdBlock{{spans: []mdSpan{{text: "a", italic: true}, {text: " b"}}}}},
+		{"empty", " \n\n ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseMarkdown(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseMarkdown(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSafeLink(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"https://go.dev", "https://go.dev"},
+		{"http://go.dev/x?y=1", "http://go.dev/x?y=1"},
+		{"mailto:a@b.dev", "mailto:a@b.dev"},
+		{"https://", ""},
+		{"go.dev", ""},
+		{"javascript:alert(1)", ""},
+		{"data:text/html,x", ""},
+		{"/relative", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := safeLink(tt.input); got != tt.want {
+				t.Errorf("safeLink(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
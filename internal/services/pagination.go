@@ -0,0 +1,294 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Text is laid out into lines before it is written, so the PDF service can
+// measure a block and decide where pages break instead of leaving it to
+// gofpdf's automatic page break.
+
+// Orphan and widow control: a block split across pages keeps at least this
+// many lines on each side of the break
+const (
+	orphanLines = 2 // lines left at the bottom of the page
+	widowLines  = 2 // lines carried to the top of the next page
+)
+
+// spaceLeft returns the height left above the bottom margin of the page
+func spaceLeft(pdf *gofpdf.Fpdf) float64 {
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottom := pdf.GetMargins()
+	return pageHeight - bottom - pdf.GetY()
+}
+
+// pageContentHeight returns the height between the top and bottom margins
+func pageContentHeight(pdf *gofpdf.Fpdf) float64 {
+	_, pageHeight := pdf.GetPageSize()
+	_, top, _, bottom := pdf.GetMargins()
+	return pageHeight - top - bottom
+}
+
+// atPageTop reports whether nothing has been written yet on the current
+// page of the current column, where moving to the next page gains nothing
+func atPageTop(pdf *gofpdf.Fpdf) bool {
+	_, top, _, _ := pdf.GetMargins()
+	return pdf.GetY() <= top+0.01
+}
+
+// breakPage moves to the top of the next page, reusing the page the other
+// column of the two-column layout already created
+func breakPage(pdf *gofpdf.Fpdf) {
+	if pdf.PageNo() < pdf.PageCount() {
+		continueOnPage(pdf, pdf.PageNo()+1)
+		return
+	}
+	pdf.AddPage()
+}
+
+// keepTogether starts a new page unless height fits in the space left
+func keepTogether(pdf *gofpdf.Fpdf, height float64) {
+	if height > spaceLeft(pdf) && !atPageTop(pdf) {
+		breakPage(pdf)
+	}
+}
+
+// spanStyle is the font size, colors and line height text spans are
+// written with
+type spanStyle struct {
+	size       float64
+	color      Color
+	linkColor  Color
+	lineHeight float64
+}
+
+// textLine is one laid out line: runs of words sharing a font style and link
+type textLine []mdSpan
+
+// fontStyle returns the gofpdf style string of a span
+func fontStyle(span mdSpan) string {
+	style := ""
+	if span.bold {
+		style += "B"
+	}
+	if span.italic {
+		style += "I"
+	}
+	return style
+}
+
+// layoutSpans wraps styled text into lines no wider than width, measuring
+// each word in its own font style
+func (s *PDFService) layoutSpans(pdf *gofpdf.Fpdf, theme Theme, spans []mdSpan, size, width float64) []textLine {
+	var lines []textLine
+	var line textLine
+	lineWidth := 0.0
+	space := false // whether a space precedes the next word
+
+	for _, span := range spans {
+		pdf.SetFont(theme.Fonts.Family, fontStyle(span), size)
+		spaceWidth := pdf.GetStringWidth(" ")
+
+		if strings.IndexFunc(span.text, unicode.IsSpace) == 0 {
+			space = true
+		}
+		for i, word := range strings.Fields(span.text) {
+			if i > 0 {
+				space = true
+			}
+			wordWidth := pdf.GetStringWidth(word)
+			if space && len(line) > 0 {
+				if lineWidth+spaceWidth+wordWidth > width {
+					lines = append(lines, line)
+					line, lineWidth = nil, 0
+				} else {
+					word = " " + word
+					wordWidth += spaceWidth
+				}
+			}
+
+			// Extend the last run when the style is unchanged
+			if n := len(line); n > 0 && sameStyle(line[n-1], span) {
+				line[n-1].text += word
+			} else {
+				run := span
+				run.text = word
+				line = append(line, run)
+			}
+			lineWidth += wordWidth
+			space = false
+		}
+		if strings.TrimRightFunc(span.text, unicode.IsSpace) != span.text {
+			space = true
+		}
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+func sameStyle(a, b mdSpan) bool {
+	return a.bold == b.bold && a.italic == b.italic && a.link == b.link
+}
+
+// writeLine writes one laid out line at x and moves to the next line. Runs
+// with a link are clickable.
+func (s *PDFService) writeLine(pdf *gofpdf.Fpdf, theme Theme, line textLine, x float64, style spanStyle) {
+	// Runs are placed edge to edge; the cell margin is only applied once
+	// so the text lines up with the rest of the page
+	margin := pdf.GetCellMargin()
+	pdf.SetCellMargin(0)
+	pdf.SetX(x + margin)
+
+	for _, run := range line {
+		pdf.SetFont(theme.Fonts.Family, fontStyle(run), style.size)
+		if run.link != "" {
+			setTextColor(pdf, style.linkColor)
+		} else {
+			setTextColor(pdf, style.color)
+		}
+		pdf.CellFormat(pdf.GetStringWidth(run.text), style.lineHeight, run.text, "", 0, "L", false, 0, run.link)
+	}
+
+	pdf.SetCellMargin(margin)
+	pdf.SetFont(theme.Fonts.Family, "", style.size)
+	pdf.Ln(style.lineHeight)
+}
+
+// writeSpans writes styled text as wrapped lines at the left margin
+func (s *PDFService) writeSpans(pdf *gofpdf.Fpdf, theme Theme, spans []mdSpan, style spanStyle) {
+	left, _, _, _ := pdf.GetMargins()
+	for _, line := range s.layoutSpans(pdf, theme, spans, style.size, textWidth(pdf)) {
+		s.writeLine(pdf, theme, line, left, style)
+	}
+}
+
+// splitLines returns how many of n lines of a block to write on the current
+// page, which has room for fit of them. Zero moves the whole block to the
+// next page.
+func splitLines(n, fit int) int {
+	if n <= fit {
+		return n
+	}
+	k := fit
+	if n-k < widowLines {
+		k = n - widowLines
+	}
+	if k < orphanLines {
+		k = 0
+	}
+	return k
+}
+
+// bulletIndent is the hanging indent of bullet points, in mm
+const bulletIndent = 4.0
+
+// textBlock is a laid out paragraph or bullet point
+type textBlock struct {
+	bullet bool
+	lines  []textLine
+}
+
+// blockGap is the space between a paragraph and the block before it
+func blockGap(style spanStyle) float64 {
+	return style.lineHeight / 3
+}
+
+// bodyStyle is the style of descriptions
+func bodyStyle(theme Theme) spanStyle {
+	return spanStyle{
+		size:       theme.Fonts.BodySize,
+		color:      theme.Palette.Text,
+		linkColor:  theme.Palette.Accent,
+		lineHeight: theme.Spacing.ItemLineHeight,
+	}
+}
+
+// layoutBlocks lays out parsed Markdown for the current column width.
+// Bullet text wraps under its first word rather than under the bullet.
+func (s *PDFService) layoutBlocks(pdf *gofpdf.Fpdf, theme Theme, blocks []mdBlock) []textBlock {
+	width := textWidth(pdf)
+	var laidOut []textBlock
+	for _, block := range blocks {
+		w := width
+		if block.bullet {
+			w -= bulletIndent
+		}
+		lines := s.layoutSpans(pdf, theme, block.spans, theme.Fonts.BodySize, w)
+		if len(lines) > 0 {
+			laidOut = append(laidOut, textBlock{bullet: block.bullet, lines: lines})
+		}
+	}
+	return laidOut
+}
+
+// gapBefore reports whether block i is separated from the previous block;
+// consecutive bullets are not
+func gapBefore(blocks []textBlock, i int) bool {
+	return i > 0 && (!blocks[i].bullet || !blocks[i-1].bullet)
+}
+
+// blocksHeight returns the height writeBlocks needs for blocks
+func blocksHeight(blocks []textBlock, style spanStyle) float64 {
+	height := 0.0
+	for i, block := range blocks {
+		if gapBefore(blocks, i) {
+			height += blockGap(style)
+		}
+		height += float64(len(block.lines)) * style.lineHeight
+	}
+	return height
+}
+
+// writeBlocks writes laid out blocks. A block that does not fit on the page
+// is split following the orphan and widow rules, or moved to the next page
+// when it is too short to split.
+func (s *PDFService) writeBlocks(pdf *gofpdf.Fpdf, theme Theme, blocks []textBlock, style spanStyle) {
+	left, _, _, _ := pdf.GetMargins()
+
+	for i, block := range blocks {
+		if gapBefore(blocks, i) {
+			pdf.Ln(blockGap(style))
+		}
+
+		lines := block.lines
+		first := true
+		for len(lines) > 0 {
+			k := splitLines(len(lines), int(spaceLeft(pdf)/style.lineHeight+0.001))
+			if k == 0 && atPageTop(pdf) {
+				// Taller than a page: fill it
+				k = max(1, int(pageContentHeight(pdf)/style.lineHeight))
+			}
+			for _, line := range lines[:min(k, len(lines))] {
+				x := left
+				if block.bullet {
+					x += bulletIndent
+					if first {
+						s.writeBullet(pdf, theme, left, style)
+					}
+				}
+				s.writeLine(pdf, theme, line, x, style)
+				first = false
+			}
+			lines = lines[min(k, len(lines)):]
+			if len(lines) > 0 {
+				breakPage(pdf)
+			}
+		}
+	}
+}
+
+// writeBullet draws the bullet mark at x on the current line
+func (s *PDFService) writeBullet(pdf *gofpdf.Fpdf, theme Theme, x float64, style spanStyle) {
+	y := pdf.GetY()
+	pdf.SetXY(x, y)
+	pdf.SetFont(theme.Fonts.Family, "", style.size)
+	setTextColor(pdf, theme.Palette.Accent)
+	pdf.CellFormat(bulletIndent, style.lineHeight, "•", "", 0, "L", false, 0, "")
+	pdf.SetY(y)
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		n, fit int
+		want   int
+	}{
+		{"fits", 5, 5, 5},
+		{"fits with room", 3, 10, 3},
+		{"split", 10, 6, 6},
+		{"widow pulls a line over", 10, 9, 8},
+		{"orphan moves the block", 10, 1, 0},
+		{"nothing fits", 10, 0, 0},
+		{"short block", 3, 2, 0},
+		{"two on each side", 4, 3, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitLines(tt.n, tt.fit); got != tt.want {
+				t.Errorf("splitLines(%d, %d) = %d, want %d", tt.n, tt.fit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeepTogether(t *testing.T) {
+	tests := []struct {
+		name      string
+		y, height float64
+		pages     int
+	}{
+		{"fits", 100, 50, 1},
+		{"too tall for the space left", 250, 50, 2},
+		{"taller than a page at the top", -1, 400, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdf := gofpdf.New("P", "mm", "A4", "")
+			pdf.SetAutoPageBreak(false, 15)
+			pdf.AddPage()
+			if tt.y >= 0 {
+				pdf.SetY(tt.y)
+			}
+			keepTogether(pdf, tt.height)
+			if got := pdf.PageCount(); got != tt.pages {
+				t.Errorf("keepTogether(%v) at y %v: %d pages, want %d", tt.height, tt.y, got, tt.pages)
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"math"
 	"strings"
 
 	"cv-generator/internal/models"
@@ -13,417 +15,633 @@ import (
 
 type PDFService struct{}
 
-// Translation maps for different languages
-var translations = map[string]map[string]string{
-	"en": {
-		// Section headers
-		"SUMMARY":    "SUMMARY",
-		"EXPERIENCE": "EXPERIENCE",
-		"EDUCATION":  "EDUCATION",
-		"SKILLS":     "SKILLS",
-		"LANGUAGES":  "LANGUAGES",
-		// Common words
-		"Present": "Present",
-		"at":      "at",
-		// Skill levels - from Spanish to English
-		"Básico":     "Basic",
-		"básico":     "Basic",
-		"Intermedio": "Intermediate",
-		"intermedio": "Intermediate",
-		"Avanzado":   "Advanced",
-		"avanzado":   "Advanced",
-		"Experto":    "Expert",
-		"experto":    "Expert",
-		// Already English skill levels (no translation needed)
-		"Basic":        "Basic",
-		"basic":        "Basic",
-		"Intermediate": "Intermediate",
-		"intermediate": "Intermediate",
-		"Advanced":     "Advanced",
-		"advanced":     "Advanced",
-		"Expert":       "Expert",
-		"expert":       "Expert",
-	},
-	"es": {
-		// Section headers
-		"SUMMARY":    "RESUMEN",
-		"EXPERIENCE": "EXPERIENCIA",
-		"EDUCATION":  "EDUCACIÓN",
-		"SKILLS":     "HABILIDADES",
-		"LANGUAGES":  "IDIOMAS",
-		// Common words
-		"Present": "Presente",
-		"at":      "en",
-		// Skill levels - from English to Spanish
-		"Basic":        "Básico",
-		"basic":        "Básico",
-		"Intermediate": "Intermedio",
-		"intermediate": "Intermedio",
-		"Advanced":     "Avanzado",
-		"advanced":     "Avanzado",
-		"Expert":       "Experto",
-		"expert":       "Experto",
-		// Already Spanish skill levels (no translation needed)
-		"Básico":     "Básico",
-		"básico":     "Básico",
-		"Intermedio": "Intermedio",
-		"intermedio": "Intermedio",
-		"Avanzado":   "Avanzado",
-		"avanzado":   "Avanzado",
-		"Experto":    "Experto",
-		"experto":    "experto",
-	},
-}
-
-// translate converts text based on the target language
-func (s *PDFService) translate(text, targetLang string) string {
-	if targetLang == "" {
-		targetLang = "en" // default to English
-	}
-
-	if langMap, exists := translations[targetLang]; exists {
-		if translated, exists := langMap[text]; exists {
-			return translated
-		}
-	}
+func NewPDFService() *PDFService {
+	return &PDFService{}
+}
 
-	return text // fallback to original text if no translation found
+// PDFInfo describes a generated PDF
+type PDFInfo struct {
+	Pages int
+	Scale float64 // 1 unless the CV was shrunk to fit cv.FitPages
 }
 
-func NewPDFService() *PDFService {
-	log.Println("🔧 Initializing PDF service with gofpdf...")
-	log.Println("✅ PDF service initialized - no external dependencies required!")
-	return &PDFService{}
+// FitError reports that a CV does not fit in the requested number of pages
+// even at the theme's minimum scale
+type FitError struct {
+	FitPages int     // requested maximum
+	Pages    int     // pages needed at MinScale
+	MinScale float64 // smallest scale tried
+}
+
+func (e *FitError) Error() string {
+	return fmt.Sprintf("needs %d pages at the minimum scale %.2f, more than the %d requested", e.Pages, e.MinScale, e.FitPages)
+}
+
+// fitStep is how much the scale is reduced on each fitPages attempt
+const fitStep = 0.05
+
+// GenerateCV renders cv as a PDF document. ctx only carries logging
+// attributes such as the request ID.
+func (s *PDFService) GenerateCV(ctx context.Context, cv models.CV) ([]byte, error) {
+	pdf, _, err := s.Render(ctx, cv)
+	return pdf, err
 }
 
-// cleanText ensures text is properly encoded for PDF and fixes common encoding issues
-func (s *PDFService) cleanText(text string) string {
-	// Convert common problematic characters from bad UTF-8 encoding
-	replacements := map[string]string{
-		"Ã¡":           "á",
-		"Ã©":           "é",
-		"Ã­":           "í",
-		"Ã³":           "ó",
-		"Ãº":           "ú",
-		"Ã±":           "ñ",
-		"BÃ¡sico":      "Básico",
-		"bÃ¡sico":      "básico",
-		"BÃ sico":      "Básico",
-		"bÃ sico":      "básico",
-		"Basico":       "Básico",
-		"basico":       "básico",
-		"intermedio":   "Intermedio",
-		"avanzado":     "Avanzado",
-		"experto":      "Experto",
-		"EducaciÃ³n":   "Educación",
-		"ExperiÃ©ncia": "Experiencia",
-		"HabilidÃ¡des": "Habilidades",
+// Render renders cv as a PDF document and reports its page count and scale.
+// When cv.FitPages is set, the CV is rendered again at decreasing scales
+// until it fits; if it does not fit at the theme's MinScale, a *FitError is
+// returned rather than shrinking the text further.
+func (s *PDFService) Render(ctx context.Context, cv models.CV) ([]byte, PDFInfo, error) {
+	theme, ok := GetTheme(cv.Theme)
+	if !ok {
+		return nil, PDFInfo{}, fmt.Errorf("unknown theme %q", cv.Theme)
 	}
+	slog.DebugContext(ctx, "generating PDF", "language", cv.Language, "theme", theme.Name, "fitPages", cv.FitPages)
 
-	result := text
-	for bad, good := range replacements {
-		result = strings.ReplaceAll(result, bad, good)
+	// The CV may override the theme's page size
+	pageSize := theme.PageSize
+	if cv.PageSize != "" {
+		if !IsPageSize(cv.PageSize) {
+			return nil, PDFInfo{}, fmt.Errorf("unsupported page size %q", cv.PageSize)
+		}
+		pageSize = cv.PageSize
 	}
 
-	// Remove any remaining null bytes or invalid characters
-	result = strings.ReplaceAll(result, "\x00", "")
-	result = strings.TrimSpace(result)
+	// The embedded fonts only cover the Basic Multilingual Plane
+	cv = mapStrings(cv, bmpText)
 
-	return result
+	scale := 1.0
+	pdf, err := s.build(theme, pageSize, cv)
+	for step := 1; err == nil && cv.FitPages > 0 && pdf.PageCount() > cv.FitPages; step++ {
+		next := math.Round((1-float64(step)*fitStep)*100) / 100
+		if next < theme.MinScale {
+			return nil, PDFInfo{}, &FitError{FitPages: cv.FitPages, Pages: pdf.PageCount(), MinScale: scale}
+		}
+		slog.DebugContext(ctx, "PDF too long, shrinking", "pages", pdf.PageCount(), "scale", scale, "next", next)
+		scale = next
+		pdf, err = s.build(theme.scaled(scale), pageSize, cv)
+	}
+	if err != nil {
+		return nil, PDFInfo{}, err
+	}
+
+	// Generate PDF bytes
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		return nil, PDFInfo{}, err
+	}
+
+	info := PDFInfo{Pages: pdf.PageCount(), Scale: scale}
+	slog.DebugContext(ctx, "PDF generated", "pages", info.Pages, "scale", info.Scale, "bytes", buffer.Len())
+	return buffer.Bytes(), info, nil
 }
 
-func (s *PDFService) GenerateCV(cv models.CV) ([]byte, error) {
-	log.Println("🎨 Generating PDF with gofpdf...")
-	log.Printf("🌐 PDF Language: %s", cv.Language)
-	log.Printf("📋 CV Language Field: '%s' (length: %d)", cv.Language, len(cv.Language))
+// build lays out cv with theme on a new document
+func (s *PDFService) build(theme Theme, pageSize string, cv models.CV) (*gofpdf.Fpdf, error) {
+	pdf := gofpdf.New("P", "mm", pageSize, "")
 
-	// Create new PDF document with better UTF-8 handling
-	pdf := gofpdf.New("P", "mm", "A4", "")
+	// Embed UTF-8 fonts so any Unicode text renders correctly
+	if err := registerFonts(pdf); err != nil {
+		return nil, fmt.Errorf("register fonts: %w", err)
+	}
 
-	// Configure translator to handle non-ASCII characters
-	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	s.setMetadata(pdf, cv)
 
+	if theme.Sidebar != nil {
+		s.renderSidebarLayout(pdf, theme, cv)
+	} else {
+		s.renderSingleColumn(pdf, theme, cv)
+	}
+	s.decoratePages(pdf, theme, cv)
+
+	return pdf, pdf.Error()
+}
+
+// setMetadata fills the document information dictionary that PDF viewers
+// and applicant tracking systems read
+func (s *PDFService) setMetadata(pdf *gofpdf.Fpdf, cv models.CV) {
+	meta := metadata(cv)
+	pdf.SetTitle(meta.title, true)
+	pdf.SetAuthor(meta.author, true)
+	pdf.SetSubject(meta.subject, true)
+	pdf.SetKeywords(strings.Join(meta.keywords, ", "), true)
+	pdf.SetCreator("CV Generator", true)
+}
+
+// renderSingleColumn writes the classic layout: a full-width header followed
+// by every section in one column
+func (s *PDFService) renderSingleColumn(pdf *gofpdf.Fpdf, theme Theme, cv models.CV) {
 	// Set margins
-	pdf.SetMargins(25, 25, 25)
-	pdf.SetAutoPageBreak(true, 25)
+	margin := theme.Spacing.Margin
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
 
 	// Add first page
 	pdf.AddPage()
 
-	// Define colors (monochromatic theme)
-	textColor := map[string]int{"r": 55, "g": 53, "b": 47}         // #37352f
-	lightTextColor := map[string]int{"r": 111, "g": 111, "b": 111} // #6f6f6f
-	separatorColor := map[string]int{"r": 227, "g": 226, "b": 224} // #e3e2e0
-
 	// Header - Name
-	pdf.SetTextColor(textColor["r"], textColor["g"], textColor["b"])
-	pdf.SetFont("Arial", "B", 18)
-	cleanName := tr(s.cleanText(cv.PersonalInfo.FullName))
-	pdf.CellFormat(0, 12, cleanName, "", 1, "L", false, 0, "")
-	pdf.Ln(3)
+	s.addName(pdf, theme, cv.PersonalInfo.FullName)
+
+	// Contact Information, on one line wrapped as needed
+	if contactParts := contactParts(cv.PersonalInfo); len(contactParts) > 0 {
+		var spans []mdSpan
+		for i, part := range contactParts {
+			if i > 0 {
+				spans = append(spans, mdSpan{text: " • "})
+			}
+			spans = append(spans, part)
+		}
+		s.writeSpans(pdf, theme, spans, spanStyle{
+			size:       theme.Fonts.ContactSize,
+			color:      theme.Palette.LightText,
+			linkColor:  theme.Palette.LightText,
+			lineHeight: theme.Spacing.ContactLineHeight,
+		})
+	}
 
-	// Contact Information
-	pdf.SetFont("Arial", "", 9)
-	pdf.SetTextColor(lightTextColor["r"], lightTextColor["g"], lightTextColor["b"])
+	pdf.Ln(theme.Spacing.AfterContact)
 
-	var contactParts []string
-	if cv.PersonalInfo.Email != "" {
-		contactParts = append(contactParts, tr(s.cleanText(cv.PersonalInfo.Email)))
-	}
-	if cv.PersonalInfo.Phone != "" {
-		contactParts = append(contactParts, tr(s.cleanText(cv.PersonalInfo.Phone)))
-	}
-	if cv.PersonalInfo.Location != "" {
-		contactParts = append(contactParts, tr(s.cleanText(cv.PersonalInfo.Location)))
+	// Add separator line
+	drawSeparator(pdf, theme)
+	pdf.Ln(theme.Spacing.AfterHeader)
+
+	for _, layout := range cv.Sections() {
+		s.addLayoutSection(pdf, theme, cv, layout)
 	}
-	if cv.PersonalInfo.LinkedIn != "" {
-		contactParts = append(contactParts, "LinkedIn: "+tr(s.cleanText(cv.PersonalInfo.LinkedIn)))
+}
+
+// renderSidebarLayout writes the two-column layout. The sidebar on the left
+// holds contact details, skills and languages; the main column holds the
+// name and every other section. Both follow the CV's section order. Each
+// column is written in its own pass and flows onto following pages
+// independently.
+func (s *PDFService) renderSidebarLayout(pdf *gofpdf.Fpdf, theme Theme, cv models.CV) {
+	sidebar := theme.Sidebar
+	margin := theme.Spacing.Margin
+	pageWidth, pageHeight := pdf.GetPageSize()
+
+	pdf.SetTopMargin(margin)
+	pdf.SetAutoPageBreak(true, margin)
+
+	// Tint the sidebar on every page before any content is written
+	if bg := sidebar.Background; bg != nil {
+		pdf.SetHeaderFunc(func() {
+			pdf.SetFillColor(bg.R, bg.G, bg.B)
+			pdf.Rect(0, 0, sidebar.Width, pageHeight, "F")
+		})
 	}
-	if cv.PersonalInfo.GitHub != "" {
-		contactParts = append(contactParts, "GitHub: "+tr(s.cleanText(cv.PersonalInfo.GitHub)))
+
+	// When a column reaches the bottom of a page that is followed by a page
+	// the other column already created, continue there instead of appending
+	pdf.SetAcceptPageBreakFunc(func() bool {
+		if pdf.PageNo() < pdf.PageCount() {
+			continueOnPage(pdf, pdf.PageNo()+1)
+			return false
+		}
+		return true
+	})
+
+	// Sidebar column
+	pdf.SetLeftMargin(sidebar.Padding)
+	pdf.SetRightMargin(pageWidth - sidebar.Width + sidebar.Padding)
+	pdf.AddPage()
+
+	if contactParts := contactParts(cv.PersonalInfo); len(contactParts) > 0 {
+		s.addListSection(pdf, theme, translate("CONTACT", cv.Language), contactParts, spanStyle{
+			size:       theme.Fonts.ContactSize,
+			color:      theme.Palette.LightText,
+			linkColor:  theme.Palette.LightText,
+			lineHeight: theme.Spacing.BodyLineHeight,
+		})
 	}
-	if cv.PersonalInfo.Website != "" {
-		contactParts = append(contactParts, tr(s.cleanText(cv.PersonalInfo.Website)))
+	sections := cv.Sections()
+	for _, layout := range sections {
+		if inSidebar(layout.Section) {
+			s.addLayoutSection(pdf, theme, cv, layout)
+		}
 	}
 
-	if len(contactParts) > 0 {
-		contactInfo := strings.Join(contactParts, " • ")
-		// Split long contact info into multiple lines if needed
-		lines := s.splitText(pdf, contactInfo, 160)
-		for _, line := range lines {
-			pdf.CellFormat(0, 5, line, "", 1, "L", false, 0, "")
+	// Main column, starting again from the top of the first page
+	mainLeft := sidebar.Width + sidebar.Gutter
+	pdf.SetLeftMargin(mainLeft)
+	pdf.SetRightMargin(margin)
+	continueOnPage(pdf, 1)
+
+	s.addName(pdf, theme, cv.PersonalInfo.FullName)
+	drawSeparator(pdf, theme)
+	pdf.Ln(theme.Spacing.AfterHeader)
+
+	for _, layout := range sections {
+		if !inSidebar(layout.Section) {
+			s.addLayoutSection(pdf, theme, cv, layout)
 		}
 	}
 
-	pdf.Ln(5)
+	// Leave the last page current so the document is closed correctly
+	pdf.SetPage(pdf.PageCount())
+}
 
-	// Add separator line
-	pdf.SetDrawColor(separatorColor["r"], separatorColor["g"], separatorColor["b"])
-	pdf.Line(25, pdf.GetY(), 185, pdf.GetY())
-	pdf.Ln(8)
+// continueOnPage moves to the top of an existing page and re-emits the
+// current font, colors and line width, since each page has its own content
+// stream and graphics state
+func continueOnPage(pdf *gofpdf.Fpdf, page int) {
+	pdf.SetPage(page)
+	_, top, _, _ := pdf.GetMargins()
+	pdf.SetY(top)
+
+	size, _ := pdf.GetFontSize()
+	pdf.SetFontSize(size)
+	pdf.SetFillColor(pdf.GetFillColor())
+	pdf.SetDrawColor(pdf.GetDrawColor())
+	pdf.SetLineWidth(pdf.GetLineWidth())
+}
 
-	// Summary Section
-	if cv.PersonalInfo.Summary != "" {
-		s.addSection(pdf, tr, s.translate("SUMMARY", cv.Language), s.cleanText(cv.PersonalInfo.Summary), textColor, lightTextColor, separatorColor)
+// decoratePages draws the running header and the page number footer once
+// the page count is known. A one-page CV gets neither. Both are written in
+// the page margins, so they never move the content.
+func (s *PDFService) decoratePages(pdf *gofpdf.Fpdf, theme Theme, cv models.CV) {
+	pages := pdf.PageCount()
+	if pages < 2 || (!theme.Page.PageNumbers && !theme.Page.RunningHeader) {
+		return
 	}
 
-	// Experience Section
-	if len(cv.Experience) > 0 {
-		s.addExperienceSection(pdf, tr, cv.Experience, cv.Language, textColor, lightTextColor, separatorColor)
+	// Align with the main column of the two-column layout
+	margin := theme.Spacing.Margin
+	pageWidth, pageHeight := pdf.GetPageSize()
+	left := margin
+	if theme.Sidebar != nil {
+		left = theme.Sidebar.Width + theme.Sidebar.Gutter
+	}
+	width := pageWidth - left - margin
+	lineHeight := theme.Page.FontSize * 0.5
+
+	// The header shows the name and the first two contact details
+	header := []string{cleanText(cv.PersonalInfo.FullName)}
+	for i, part := range contactParts(cv.PersonalInfo) {
+		if i == 2 {
+			break
+		}
+		header = append(header, part.text)
 	}
 
-	// Education Section
-	if len(cv.Education) > 0 {
-		s.addEducationSection(pdf, tr, cv.Education, cv.Language, textColor, lightTextColor, separatorColor)
+	pdf.SetAutoPageBreak(false, 0)
+	for page := 1; page <= pages; page++ {
+		continueOnPage(pdf, page)
+		pdf.SetFont(theme.Fonts.Family, "", theme.Page.FontSize)
+		setTextColor(pdf, theme.Palette.LightText)
+
+		if theme.Page.RunningHeader && page > 1 {
+			pdf.SetXY(left, (margin-lineHeight)/2)
+			pdf.CellFormat(width, lineHeight, joinNonEmpty(" • ", header...), "", 0, "L", false, 0, "")
+		}
+		if theme.Page.PageNumbers {
+			pdf.SetXY(left, pageHeight-(margin+lineHeight)/2)
+			footer := fmt.Sprintf(translate("Page %d of %d", cv.Language), page, pages)
+			pdf.CellFormat(width, lineHeight, footer, "", 0, "C", false, 0, "")
+		}
+	}
+	pdf.SetPage(pages)
+}
+
+// inSidebar reports whether a section goes to the sidebar column of the
+// two-column layout
+func inSidebar(section string) bool {
+	return section == models.SectionSkills || section == models.SectionLanguages
+}
+
+// addLayoutSection writes one section of the CV layout if it has content
+func (s *PDFService) addLayoutSection(pdf *gofpdf.Fpdf, theme Theme, cv models.CV, layout models.SectionLayout) {
+	// Built-in titles are capitals in every language; overrides stay as
+	// written
+	title := sectionTitle(layout, cv.Language)
+	lang := cv.Language
+
+	if entries, ok := sectionEntries(cv, layout.Section); ok {
+		if len(entries) > 0 {
+			s.addEntries(pdf, theme, title, entries)
+		}
+		return
 	}
 
-	// Skills Section
-	if len(cv.Skills) > 0 {
-		s.addSkillsSection(pdf, tr, cv.Skills, cv.Language, textColor, lightTextColor, separatorColor)
+	switch layout.Section {
+	case models.SectionSummary:
+		if cv.PersonalInfo.Summary != "" {
+			s.addSection(pdf, theme, title, cleanText(cv.PersonalInfo.Summary))
+		}
+	case models.SectionCustom:
+		for _, section := range cv.CustomSections {
+			s.addCustomSection(pdf, theme, section, lang)
+		}
+	case models.SectionSkills:
+		if len(cv.Skills) > 0 {
+			s.addSkillsSection(pdf, theme, title, cv.Skills, lang)
+		}
+	case models.SectionLanguages:
+		if len(cv.Languages) > 0 {
+			s.addLanguagesSection(pdf, theme, title, cv.Languages, lang)
+		}
 	}
+}
+
+func (s *PDFService) addName(pdf *gofpdf.Fpdf, theme Theme, name string) {
+	setTextColor(pdf, theme.Palette.Accent)
+	pdf.SetFont(theme.Fonts.Family, theme.Fonts.NameStyle, theme.Fonts.NameSize)
+	pdf.CellFormat(0, theme.Spacing.NameHeight, cleanText(name), "", 1, "L", false, 0, "")
+	pdf.Ln(theme.Spacing.AfterName)
+}
+
+// addListSection writes a section with one wrapped line group per item, as
+// used in the narrow sidebar column
+func (s *PDFService) addListSection(pdf *gofpdf.Fpdf, theme Theme, title string, items []mdSpan, style spanStyle) {
+	s.addSectionTitle(pdf, theme, title, style.lineHeight)
 
-	// Languages Section
-	if len(cv.Languages) > 0 {
-		s.addLanguagesSection(pdf, tr, cv.Languages, cv.Language, textColor, lightTextColor, separatorColor)
+	for _, item := range items {
+		s.writeSpans(pdf, theme, []mdSpan{item}, style)
 	}
+	pdf.Ln(theme.Spacing.AfterSection)
+}
 
-	// Generate PDF bytes
-	buffer := &bytes.Buffer{}
-	err := pdf.Output(buffer)
-	if err != nil {
-		log.Printf("❌ PDF generation failed: %v", err)
-		return nil, err
+// textWidth returns the width available between the left and right margins
+func textWidth(pdf *gofpdf.Fpdf) float64 {
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	return pageWidth - left - right
+}
+
+func setTextColor(pdf *gofpdf.Fpdf, c Color) {
+	pdf.SetTextColor(c.R, c.G, c.B)
+}
+
+// drawSeparator draws a horizontal rule across the text width at the current
+// position, using the theme's separator style
+func drawSeparator(pdf *gofpdf.Fpdf, theme Theme) {
+	left, _, _, _ := pdf.GetMargins()
+	right := left + textWidth(pdf)
+	y := pdf.GetY()
+	c := theme.Palette.Separator
+	pdf.SetDrawColor(c.R, c.G, c.B)
+
+	switch theme.Separator {
+	case SeparatorNone:
+		return
+	case SeparatorThick:
+		pdf.SetLineWidth(0.6)
+		pdf.Line(left, y, right, y)
+	case SeparatorDouble:
+		pdf.SetLineWidth(0.2)
+		pdf.Line(left, y, right, y)
+		pdf.Line(left, y+0.8, right, y+0.8)
+		pdf.SetY(y + 0.8)
+	default:
+		pdf.SetLineWidth(0.2)
+		pdf.Line(left, y, right, y)
 	}
+	pdf.SetLineWidth(0.2)
+}
 
-	buf := buffer.Bytes()
-	log.Printf("✅ PDF generated successfully, size: %d bytes", len(buf))
+// sectionTitleHeight is the height addSectionTitle writes, separator
+// included
+func sectionTitleHeight(theme Theme) float64 {
+	return theme.Spacing.SectionTitleHeight + 1 + theme.Spacing.AfterSectionTitle
+}
 
-	return buf, nil
+// addSectionTitle writes a section title followed by the theme separator,
+// and adds it to the document outline. next is the height of the content
+// that must follow the title on the same page, so a title is never the last
+// thing on a page.
+func (s *PDFService) addSectionTitle(pdf *gofpdf.Fpdf, theme Theme, title string, next float64) {
+	keepTogether(pdf, sectionTitleHeight(theme)+next)
+
+	setTextColor(pdf, theme.Palette.Accent)
+	pdf.SetFont(theme.Fonts.Family, theme.Fonts.SectionTitleStyle, theme.Fonts.SectionTitleSize)
+	// Bookmark titles are only encoded as UTF-16 once a UTF-8 font is set
+	pdf.Bookmark(cleanText(title), 0, -1)
+	pdf.CellFormat(0, theme.Spacing.SectionTitleHeight, cleanText(title), "", 1, "L", false, 0, "")
+
+	drawSeparator(pdf, theme)
+	pdf.Ln(theme.Spacing.AfterSectionTitle)
 }
 
-func (s *PDFService) addSection(pdf *gofpdf.Fpdf, tr func(string) string, title, content string, textColor, lightTextColor, separatorColor map[string]int) {
-	// Section title
-	pdf.SetTextColor(textColor["r"], textColor["g"], textColor["b"])
-	pdf.SetFont("Arial", "B", 10)
-	pdf.CellFormat(0, 6, tr(s.cleanText(title)), "", 1, "L", false, 0, "")
+func (s *PDFService) addSection(pdf *gofpdf.Fpdf, theme Theme, title, content string) {
+	style := spanStyle{
+		size:       theme.Fonts.BodySize,
+		color:      theme.Palette.Text,
+		linkColor:  theme.Palette.Accent,
+		lineHeight: theme.Spacing.BodyLineHeight,
+	}
+	lines := s.layoutSpans(pdf, theme, []mdSpan{{text: content}}, style.size, textWidth(pdf))
+
+	s.addSectionTitle(pdf, theme, title, float64(min(orphanLines, len(lines)))*style.lineHeight)
+	s.writeBlocks(pdf, theme, []textBlock{{lines: lines}}, style)
+	pdf.Ln(theme.Spacing.AfterSection)
+}
 
-	// Section separator
-	pdf.SetDrawColor(separatorColor["r"], separatorColor["g"], separatorColor["b"])
-	pdf.Line(25, pdf.GetY(), 185, pdf.GetY())
-	pdf.Ln(3)
+// entryLayout is an entry laid out for the current column
+type entryLayout struct {
+	title string
+	head  []mdSpan    // wrapped subtitle and detail lines, with their link
+	body  []textBlock // description and highlights
+}
 
-	// Section content
-	pdf.SetFont("Arial", "", 10)
-	pdf.SetTextColor(textColor["r"], textColor["g"], textColor["b"])
+// headHeight returns the height of the title, subtitle and detail lines
+func (l entryLayout) headHeight(theme Theme) float64 {
+	return theme.Spacing.BodyLineHeight + float64(len(l.head))*theme.Spacing.ItemLineHeight
+}
 
-	lines := s.splitText(pdf, tr(content), 160)
-	for _, line := range lines {
-		pdf.CellFormat(0, 5, line, "", 1, "L", false, 0, "")
+func (l entryLayout) height(theme Theme) float64 {
+	return l.headHeight(theme) + blocksHeight(l.body, bodyStyle(theme))
+}
+
+// minHeight returns the height of the head and the first lines of the body,
+// the part of an entry that is never split
+func (l entryLayout) minHeight(theme Theme) float64 {
+	height := l.headHeight(theme)
+	if len(l.body) > 0 {
+		height += float64(min(orphanLines, len(l.body[0].lines))) * theme.Spacing.ItemLineHeight
 	}
-	pdf.Ln(5)
+	return height
 }
 
-func (s *PDFService) addExperienceSection(pdf *gofpdf.Fpdf, tr func(string) string, experiences []models.Experience, lang string, textColor, lightTextColor, separatorColor map[string]int) {
-	// Section title
-	pdf.SetTextColor(textColor["r"], textColor["g"], textColor["b"])
-	pdf.SetFont("Arial", "B", 10)
-	pdf.CellFormat(0, 6, tr(s.translate("EXPERIENCE", lang)), "", 1, "L", false, 0, "")
+// keepHeight returns the height to keep on one page: the whole entry when
+// it fits on a page, otherwise its minimum
+func (l entryLayout) keepHeight(pdf *gofpdf.Fpdf, theme Theme, above float64) float64 {
+	if height := l.height(theme); above+height <= pageContentHeight(pdf) {
+		return height
+	}
+	return l.minHeight(theme)
+}
 
-	// Section separator
-	pdf.SetDrawColor(separatorColor["r"], separatorColor["g"], separatorColor["b"])
-	pdf.Line(25, pdf.GetY(), 185, pdf.GetY())
-	pdf.Ln(3)
+// addEntries writes a section made of entries. Entries are kept on one page
+// and only split when taller than a page.
+func (s *PDFService) addEntries(pdf *gofpdf.Fpdf, theme Theme, title string, entries []entry) {
+	var layouts []entryLayout
+	for _, e := range entries {
+		layouts = append(layouts, s.layoutEntry(pdf, theme, e))
+	}
 
-	for i, exp := range experiences {
-		// Item title
-		pdf.SetFont("Arial", "B", 10)
-		pdf.SetTextColor(textColor["r"], textColor["g"], textColor["b"])
-		atWord := s.translate("at", lang)
-		title := fmt.Sprintf("%s %s %s", tr(s.cleanText(exp.Position)), atWord, tr(s.cleanText(exp.Company)))
-		pdf.CellFormat(0, 5, title, "", 1, "L", false, 0, "")
+	next := 0.0
+	if len(layouts) > 0 {
+		next = layouts[0].keepHeight(pdf, theme, sectionTitleHeight(theme))
+	}
+	s.addSectionTitle(pdf, theme, title, next)
 
-		// Item subtitle (dates)
-		pdf.SetFont("Arial", "I", 9)
-		pdf.SetTextColor(lightTextColor["r"], lightTextColor["g"], lightTextColor["b"])
-		dateRange := tr(s.cleanText(exp.StartDate))
-		if exp.EndDate != "" {
-			dateRange += " - " + tr(s.cleanText(exp.EndDate))
-		} else {
-			dateRange += " - " + tr(s.translate("Present", lang))
-		}
-		pdf.CellFormat(0, 4, dateRange, "", 1, "L", false, 0, "")
-
-		// Description
-		if exp.Description != "" {
-			pdf.SetFont("Arial", "", 10)
-			pdf.SetTextColor(textColor["r"], textColor["g"], textColor["b"])
-			lines := s.splitText(pdf, tr(s.cleanText(exp.Description)), 160)
-			for _, line := range lines {
-				pdf.CellFormat(0, 4, line, "", 1, "L", false, 0, "")
-			}
+	for i, l := range layouts {
+		if i > 0 {
+			pdf.Ln(theme.Spacing.BetweenItems)
+			keepTogether(pdf, l.keepHeight(pdf, theme, 0))
 		}
+		s.writeEntry(pdf, theme, l)
+	}
+	pdf.Ln(theme.Spacing.AfterSection)
+}
 
-		if i < len(experiences)-1 {
-			pdf.Ln(3)
+// layoutEntry wraps the lines of an entry so it can be measured
+func (s *PDFService) layoutEntry(pdf *gofpdf.Fpdf, theme Theme, e entry) entryLayout {
+	l := entryLayout{title: cleanText(e.title)}
+
+	// Details that are a web address, such as a project or credential URL,
+	// are clickable
+	pdf.SetFont(theme.Fonts.Family, theme.Fonts.ItemSubtitleStyle, theme.Fonts.ItemSubtitleSize)
+	for _, line := range e.lines() {
+		for _, text := range s.splitText(pdf, line.text, textWidth(pdf)) {
+			l.head = append(l.head, mdSpan{text: text, link: line.link})
 		}
 	}
-	pdf.Ln(5)
-}
-
-func (s *PDFService) addEducationSection(pdf *gofpdf.Fpdf, tr func(string) string, education []models.Education, lang string, textColor, lightTextColor, separatorColor map[string]int) {
-	// Section title
-	pdf.SetTextColor(textColor["r"], textColor["g"], textColor["b"])
-	pdf.SetFont("Arial", "B", 10)
-	pdf.CellFormat(0, 6, tr(s.translate("EDUCATION", lang)), "", 1, "L", false, 0, "")
-
-	// Section separator
-	pdf.SetDrawColor(separatorColor["r"], separatorColor["g"], separatorColor["b"])
-	pdf.Line(25, pdf.GetY(), 185, pdf.GetY())
-	pdf.Ln(3)
-
-	for i, edu := range education {
-		// Item title
-		pdf.SetFont("Arial", "B", 10)
-		pdf.SetTextColor(textColor["r"], textColor["g"], textColor["b"])
-		title := fmt.Sprintf("%s - %s", tr(s.cleanText(edu.Degree)), tr(s.cleanText(edu.Institution)))
-		pdf.CellFormat(0, 5, title, "", 1, "L", false, 0, "")
-
-		// Item subtitle (dates)
-		pdf.SetFont("Arial", "I", 9)
-		pdf.SetTextColor(lightTextColor["r"], lightTextColor["g"], lightTextColor["b"])
-		dateRange := tr(s.cleanText(edu.StartDate))
-		if edu.EndDate != "" {
-			dateRange += " - " + tr(s.cleanText(edu.EndDate))
-		} else {
-			dateRange += " - " + tr(s.translate("Present", lang))
-		}
-		pdf.CellFormat(0, 4, dateRange, "", 1, "L", false, 0, "")
-
-		// Description
-		if edu.Description != "" {
-			pdf.SetFont("Arial", "", 10)
-			pdf.SetTextColor(textColor["r"], textColor["g"], textColor["b"])
-			lines := s.splitText(pdf, tr(s.cleanText(edu.Description)), 160)
-			for _, line := range lines {
-				pdf.CellFormat(0, 4, line, "", 1, "L", false, 0, "")
-			}
-		}
 
-		if i < len(education)-1 {
-			pdf.Ln(3)
+	l.body = s.layoutBlocks(pdf, theme, e.blocks())
+
+	return l
+}
+
+// writeEntry writes a titled entry with its subtitle, detail lines and an
+// optional description
+func (s *PDFService) writeEntry(pdf *gofpdf.Fpdf, theme Theme, l entryLayout) {
+	// Item title
+	pdf.SetFont(theme.Fonts.Family, theme.Fonts.ItemTitleStyle, theme.Fonts.ItemTitleSize)
+	setTextColor(pdf, theme.Palette.Text)
+	pdf.CellFormat(0, theme.Spacing.BodyLineHeight, l.title, "", 1, "L", false, 0, "")
+
+	// Item subtitle (dates) and details; links are clickable over the width
+	// of the text only
+	pdf.SetFont(theme.Fonts.Family, theme.Fonts.ItemSubtitleStyle, theme.Fonts.ItemSubtitleSize)
+	setTextColor(pdf, theme.Palette.LightText)
+	for _, line := range l.head {
+		width := 0.0
+		if line.link != "" {
+			width = pdf.GetStringWidth(line.text) + 2*pdf.GetCellMargin()
 		}
+		pdf.CellFormat(width, theme.Spacing.ItemLineHeight, line.text, "", 1, "L", false, 0, line.link)
 	}
-	pdf.Ln(5)
+
+	// Description and highlights
+	s.writeBlocks(pdf, theme, l.body, bodyStyle(theme))
 }
 
-func (s *PDFService) addSkillsSection(pdf *gofpdf.Fpdf, tr func(string) string, skills []models.Skill, lang string, textColor, lightTextColor, separatorColor map[string]int) {
-	// Section title
-	pdf.SetTextColor(textColor["r"], textColor["g"], textColor["b"])
-	pdf.SetFont("Arial", "B", 10)
-	pdf.CellFormat(0, 6, tr(s.translate("SKILLS", lang)), "", 1, "L", false, 0, "")
+// addCustomSection writes a user-defined section. Its title is written as
+// typed, without translation.
+func (s *PDFService) addCustomSection(pdf *gofpdf.Fpdf, theme Theme, section models.CustomSection, lang string) {
+	title := section.Title
+	if len(section.Entries) == 0 {
+		s.addSection(pdf, theme, title, cleanText(section.Content))
+		return
+	}
+	s.addEntries(pdf, theme, title, customEntries(section, lang))
+}
 
-	// Section separator
-	pdf.SetDrawColor(separatorColor["r"], separatorColor["g"], separatorColor["b"])
-	pdf.Line(25, pdf.GetY(), 185, pdf.GetY())
-	pdf.Ln(3)
+func (s *PDFService) addSkillsSection(pdf *gofpdf.Fpdf, theme Theme, title string, skills []models.Skill, lang string) {
+	s.addSectionTitle(pdf, theme, title, theme.Spacing.BodyLineHeight)
 
-	pdf.SetFont("Arial", "", 10)
-	pdf.SetTextColor(textColor["r"], textColor["g"], textColor["b"])
+	pdf.SetFont(theme.Fonts.Family, "", theme.Fonts.BodySize)
+	setTextColor(pdf, theme.Palette.Text)
 
-	// Display skills in columns
-	x := 25.0
-	y := pdf.GetY()
-	colWidth := 80.0
-	lineHeight := 5.0
+	// Display skills in two columns, or one in narrow columns such as the
+	// sidebar. Each row ends with a line break so page breaks flow naturally.
+	columns := 2
+	if textWidth(pdf) < 100 {
+		columns = 1
+	}
+	colWidth := textWidth(pdf) / float64(columns)
+	lineHeight := theme.Spacing.BodyLineHeight
 
 	for i, skill := range skills {
-		if i > 0 && i%2 == 0 {
-			y += lineHeight
-			x = 25.0
-		} else if i > 0 {
-			x = 105.0
-		}
+		skillText := skillLabel(skill, lang)
 
-		pdf.SetXY(x, y)
-
-		skillText := tr(s.cleanText(skill.Name))
-		if skill.Level != "" {
-			// Translate the skill level based on the target language
-			translatedLevel := s.translate(s.cleanText(skill.Level), lang)
-			skillText += " (" + tr(translatedLevel) + ")"
+		if columns == 1 {
+			for _, line := range s.splitText(pdf, skillText, colWidth) {
+				pdf.CellFormat(colWidth, lineHeight, line, "", 1, "L", false, 0, "")
+			}
+			continue
 		}
 
-		pdf.CellFormat(colWidth, lineHeight, skillText, "", 0, "L", false, 0, "")
+		ln := 0
+		if (i+1)%columns == 0 || i == len(skills)-1 {
+			ln = 1
+		}
+		pdf.CellFormat(colWidth, lineHeight, skillText, "", ln, "L", false, 0, "")
 	}
 
-	pdf.SetXY(25, y+lineHeight+5)
+	pdf.Ln(theme.Spacing.AfterSection)
 }
 
-func (s *PDFService) addLanguagesSection(pdf *gofpdf.Fpdf, tr func(string) string, languages []string, lang string, textColor, lightTextColor, separatorColor map[string]int) {
-	// Section title
-	pdf.SetTextColor(textColor["r"], textColor["g"], textColor["b"])
-	pdf.SetFont("Arial", "B", 10)
-	pdf.CellFormat(0, 6, tr(s.translate("LANGUAGES", lang)), "", 1, "L", false, 0, "")
+// Size of the dot scale drawn for LevelDots, in mm
+const (
+	levelDots       = 6 // A1 to C2; native speakers get every dot
+	levelDotRadius  = 1.1
+	levelDotSpacing = 3.4
+)
+
+// addLanguagesSection writes one language per line with its level, either as
+// text or as a dot scale depending on the theme
+func (s *PDFService) addLanguagesSection(pdf *gofpdf.Fpdf, theme Theme, title string, languages []models.Language, lang string) {
+	s.addSectionTitle(pdf, theme, title, theme.Spacing.BodyLineHeight)
+
+	lineHeight := theme.Spacing.BodyLineHeight
+	width := textWidth(pdf)
 
-	// Section separator
-	pdf.SetDrawColor(separatorColor["r"], separatorColor["g"], separatorColor["b"])
-	pdf.Line(25, pdf.GetY(), 185, pdf.GetY())
-	pdf.Ln(3)
+	for _, language := range languages {
+		name := cleanText(language.Name)
+		certificate := cleanText(language.Certificate)
 
-	pdf.SetFont("Arial", "", 10)
-	pdf.SetTextColor(textColor["r"], textColor["g"], textColor["b"])
+		pdf.SetFont(theme.Fonts.Family, "", theme.Fonts.BodySize)
+		setTextColor(pdf, theme.Palette.Text)
 
-	var cleanLanguages []string
-	for _, lang := range languages {
-		cleanLanguages = append(cleanLanguages, tr(s.cleanText(lang)))
+		if theme.Levels != LevelDots || language.Level == "" {
+			for _, line := range s.splitText(pdf, languageLabel(language, lang), width) {
+				pdf.CellFormat(0, lineHeight, line, "", 1, "L", false, 0, "")
+			}
+			continue
+		}
+
+		// Name on the left, dot scale right-aligned on the same line
+		scaleWidth := levelDots * levelDotSpacing
+		pdf.CellFormat(width-scaleWidth, lineHeight, name, "", 0, "L", false, 0, "")
+		s.drawLevelDots(pdf, theme, models.LevelRank(language.Level), lineHeight)
+		pdf.Ln(lineHeight)
+
+		if certificate != "" {
+			pdf.SetFont(theme.Fonts.Family, theme.Fonts.ItemSubtitleStyle, theme.Fonts.ItemSubtitleSize)
+			setTextColor(pdf, theme.Palette.LightText)
+			for _, line := range s.splitText(pdf, certificate, width) {
+				pdf.CellFormat(0, theme.Spacing.ItemLineHeight, line, "", 1, "L", false, 0, "")
+			}
+		}
 	}
+	pdf.Ln(theme.Spacing.AfterSection)
+}
 
-	languageText := strings.Join(cleanLanguages, " • ")
-	lines := s.splitText(pdf, languageText, 160)
-	for _, line := range lines {
-		pdf.CellFormat(0, 5, line, "", 1, "L", false, 0, "")
+// drawLevelDots draws the level scale starting at the current position,
+// filling rank dots with the accent color
+func (s *PDFService) drawLevelDots(pdf *gofpdf.Fpdf, theme Theme, rank int, lineHeight float64) {
+	x, y := pdf.GetXY()
+	cy := y + lineHeight/2
+	for i := 0; i < levelDots; i++ {
+		color := theme.Palette.Separator
+		if i < rank {
+			color = theme.Palette.Accent
+		}
+		pdf.SetFillColor(color.R, color.G, color.B)
+		pdf.Circle(x+levelDotSpacing*float64(i)+levelDotSpacing/2, cy, levelDotRadius, "F")
 	}
-	pdf.Ln(5)
 }
 
 func (s *PDFService) splitText(pdf *gofpdf.Fpdf, text string, maxWidth float64) []string {
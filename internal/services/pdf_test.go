@@ -0,0 +1,230 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"cv-generator/internal/models"
+)
+
+// longCV returns a valid CV with n experience entries of a few lines each
+func longCV(n int) models.CV {
+	cv := models.CV{PersonalInfo: models.PersonalInfo{FullName: "Zofia Nowak", Email: "zofia@example.com"}}
+	for i := 0; i < n; i++ {
+		cv.Experience = append(cv.Experience, models.Experience{
+			Company:     fmt.Sprintf("Company %d", i),
+			Position:    "Engineer",
+			StartDate:   "2020-01",
+			EndDate:     "2021-01",
+			Description: "Built and operated the payment services used by every team in the company.",
+		})
+	}
+	return cv
+}
+
+func TestRenderFitPages(t *testing.T) {
+	s := NewPDFService()
+	_, natural, err := s.Render(context.Background(), longCV(15))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if natural.Pages != 2 || natural.Scale != 1 {
+		t.Fatalf("without fitPages: %d pages at scale %v, want 2 at 1", natural.Pages, natural.Scale)
+	}
+
+	tests := []struct {
+		name      string
+		entries   int
+		fitPages  int
+		pages     int
+		shrunk    bool
+		cannotFit bool
+	}{
+		{"already fits", 15, 2, 2, false, false},
+		{"shrunk to one page", 15, 1, 1, true, false},
+		{"too long at the minimum scale", 40, 1, 0, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cv := longCV(tt.entries)
+			cv.FitPages = tt.fitPages
+			pdf, info, err := s.Render(context.Background(), cv)
+			if tt.cannotFit {
+				var fitErr *FitError
+				if !errors.As(err, &fitErr) {
+					t.Fatalf("Render() error = %v, want a *FitError", err)
+				}
+				if fitErr.FitPages != tt.fitPages || fitErr.Pages <= tt.fitPages {
+					t.Errorf("FitError = %+v", fitErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if len(pdf) == 0 || info.Pages != tt.pages {
+				t.Errorf("Render() = %d bytes, %d pages, want %d pages", len(pdf), info.Pages, tt.pages)
+			}
+			if shrunk := info.Scale < 1; shrunk != tt.shrunk {
+				t.Errorf("Render() scale = %v, shrunk %v, want %v", info.Scale, shrunk, tt.shrunk)
+			}
+		})
+	}
+}
+
+func TestRenderFitPagesMinScale(t *testing.T) {
+	for _, theme := range []string{"", "sidebar"} {
+		t.Run(theme, func(t *testing.T) {
+			cv := longCV(40)
+			cv.Theme = theme
+			cv.FitPages = 1
+			_, _, err := NewPDFService().Render(context.Background(), cv)
+			var fitErr *FitError
+			if !errors.As(err, &fitErr) {
+				t.Fatalf("Render() error = %v, want a *FitError", err)
+			}
+			minScale := mustTheme(t, theme).MinScale
+			if fitErr.MinScale < minScale {
+				t.Errorf("FitError.MinScale = %v, below the theme's %v", fitErr.MinScale, minScale)
+			}
+		})
+	}
+}
+
+// TestRenderSidebarSpill checks that when either column of the sidebar
+// theme runs past a page, the other column shares the pages it created
+// instead of adding more
+func TestRenderSidebarSpill(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries int
+		skills  int
+		pages   int
+	}{
+		{"both fit", 10, 40, 1},
+		{"main column spills", 15, 0, 2},
+		{"sidebar spills", 0, 80, 2},
+		{"both spill to the same page", 15, 80, 2},
+		{"sidebar longer than main", 15, 100, 3},
+		{"main longer than sidebar", 30, 80, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cv := longCV(tt.entries)
+			cv.Theme = "sidebar"
+			for i := 0; i < tt.skills; i++ {
+				cv.Skills = append(cv.Skills, models.Skill{Name: fmt.Sprintf("Skill %d", i)})
+			}
+			pdf, info, err := NewPDFService().Render(context.Background(), cv)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if len(pdf) == 0 || info.Pages != tt.pages {
+				t.Errorf("Render() = %d bytes, %d pages, want %d", len(pdf), info.Pages, tt.pages)
+			}
+		})
+	}
+}
+
+func TestRenderSectionTitles(t *testing.T) {
+	cv := longCV(1)
+	cv.Language = "es"
+	cv.Layout = []models.SectionLayout{{Section: models.SectionExperience, Title: "Berufserfahrung"}}
+	cv.CustomSections = []models.CustomSection{{Title: "Open source", Content: "cvgen"}}
+	content := renderUncompressed(t, cv)
+
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{"override as typed", "Berufserfahrung", true},
+		{"override not upper-cased", "BERUFSERFAHRUNG", false},
+		{"override replaces the translation", "EXPERIENCIA", false},
+		{"custom title as typed", "Open source", true},
+		{"custom title not upper-cased", "OPEN SOURCE", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Contains(content, pdfText(tt.text)); got != tt.want {
+				t.Errorf("PDF contains %q = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecoratePages(t *testing.T) {
+	tests := []struct {
+		name    string
+		theme   string
+		entries int
+		pages   int
+	}{
+		{"one page", "", 1, 1},
+		{"two pages", "", 15, 2},
+		{"sidebar one page", "sidebar", 1, 1},
+		{"sidebar two pages", "sidebar", 15, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cv := longCV(tt.entries)
+			cv.Theme = tt.theme
+			content := renderUncompressed(t, cv)
+
+			// The running header is left off the first page
+			header := strings.Count(content, pdfText("Zofia Nowak • zofia@example.com"))
+			if header != tt.pages-1 {
+				t.Errorf("running header on %d pages, want %d", header, tt.pages-1)
+			}
+			for page := 1; page <= tt.pages; page++ {
+				footer := pdfText(fmt.Sprintf("Page %d of %d", page, tt.pages))
+				if got := strings.Contains(content, footer); got != (tt.pages > 1) {
+					t.Errorf("footer %q present = %v, want %v", fmt.Sprintf("Page %d of %d", page, tt.pages), got, tt.pages > 1)
+				}
+			}
+		})
+	}
+}
+
+// renderUncompressed lays out cv with its theme and returns the PDF with
+// uncompressed content streams, so the text written can be searched
+func renderUncompressed(t *testing.T, cv models.CV) string {
+	t.Helper()
+	theme := mustTheme(t, cv.Theme)
+	pdf, err := NewPDFService().build(theme, theme.PageSize, cv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.SetCompression(false)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+// pdfText returns s as gofpdf writes it with a UTF-8 font: UTF-16BE, which
+// needs no escaping for the text used in the tests (no parentheses or
+// backslashes)
+func pdfText(s string) string {
+	var b strings.Builder
+	for _, u := range utf16.Encode([]rune(s)) {
+		b.WriteByte(byte(u >> 8))
+		b.WriteByte(byte(u))
+	}
+	return b.String()
+}
+
+func mustTheme(t *testing.T, name string) Theme {
+	t.Helper()
+	theme, ok := GetTheme(name)
+	if !ok {
+		t.Fatalf("GetTheme(%q) not found", name)
+	}
+	return theme
+}
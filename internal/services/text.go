@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"cv-generator/internal/models"
+)
+
+// TextService renders a CV as plain UTF-8 text or as GitHub-flavored
+// Markdown. Both keep to simple structures that applicant tracking systems
+// and web forms accept: no tables, no columns and one line per paragraph.
+type TextService struct {
+	markdown bool
+}
+
+// NewTextService returns a service that writes plain text, with section
+// titles underlined by ASCII rules
+func NewTextService() *TextService {
+	return &TextService{}
+}
+
+// NewMarkdownService returns a service that writes GitHub-flavored
+// Markdown, suitable for a README-style profile
+func NewMarkdownService() *TextService {
+	return &TextService{markdown: true}
+}
+
+// Media types of the generated documents
+const (
+	TextContentType     = "text/plain; charset=utf-8"
+	MarkdownContentType = "text/markdown; charset=utf-8"
+)
+
+// GenerateCV renders cv as text. ctx only carries logging attributes such
+// as the request ID.
+func (s *TextService) GenerateCV(ctx context.Context, cv models.CV) ([]byte, error) {
+	slog.DebugContext(ctx, "generating text", "language", cv.Language, "markdown", s.markdown)
+
+	var w interface {
+		flowWriter
+		String() string
+	} = &plainWriter{}
+	if s.markdown {
+		w = &markdownWriter{}
+	}
+	writeFlow(w, cv)
+	out := w.String()
+	out = strings.TrimRight(out, "\n") + "\n"
+
+	slog.DebugContext(ctx, "text generated", "bytes", len(out))
+	return []byte(out), nil
+}
+
+// plainWriter writes plain text. Bold and italic are dropped and links are
+// written after their label.
+type plainWriter struct {
+	strings.Builder
+}
+
+// sectionRule is the character that underlines section titles
+const sectionRule = "="
+
+func (w *plainWriter) writeHeader(name string, contact []mdSpan) {
+	w.WriteString(name + "\n")
+	var parts []string
+	for _, part := range contact {
+		parts = append(parts, part.text)
+	}
+	if len(parts) > 0 {
+		w.WriteString(strings.Join(parts, " | ") + "\n")
+	}
+}
+
+// writeSection writes the title underlined with a rule of the same width
+func (w *plainWriter) writeSection(title string) {
+	fmt.Fprintf(w, "\n\n%s\n%s\n", title, strings.Repeat(sectionRule, utf8.RuneCountInString(title)))
+}
+
+func (w *plainWriter) writeEntries(entries []entry) {
+	for _, e := range entries {
+		w.WriteString("\n")
+		if title := cleanText(e.title); title != "" {
+			w.WriteString(title + "\n")
+		}
+		for _, line := range e.lines() {
+			w.WriteString(line.text + "\n")
+		}
+		w.writeBlocks(e.blocks())
+	}
+}
+
+// writeBlocks writes paragraphs separated by a blank line and consecutive
+// bullets as a list
+func (w *plainWriter) writeBlocks(blocks []mdBlock) {
+	for i, block := range blocks {
+		if i == 0 || !block.bullet || !blocks[i-1].bullet {
+			w.WriteString("\n")
+		}
+		if block.bullet {
+			w.WriteString("- ")
+		}
+		for _, span := range block.spans {
+			w.WriteString(plainSpan(span))
+		}
+		w.WriteString("\n")
+	}
+}
+
+func (w *plainWriter) writeText(text string) {
+	w.WriteString("\n" + text + "\n")
+}
+
+func (w *plainWriter) writeList(items []string) {
+	w.WriteString("\n")
+	for _, item := range items {
+		w.WriteString("- " + item + "\n")
+	}
+}
+
+// plainSpan returns the text of a span, followed by its web or email
+// address when the text does not already show it
+func plainSpan(span mdSpan) string {
+	target := strings.TrimPrefix(span.link, "mailto:")
+	if target == "" || strings.HasPrefix(target, "tel:") || strings.Contains(span.text, target) {
+		return span.text
+	}
+	return span.text + " (" + target + ")"
+}
+
+// markdownWriter writes GitHub-flavored Markdown: the name as the top
+// heading, sections as second-level headings and entries as third-level
+// headings. User text is escaped so it cannot add markup or raw HTML.
+type markdownWriter struct {
+	strings.Builder
+}
+
+func (w *markdownWriter) writeHeader(name string, contact []mdSpan) {
+	w.WriteString("# " + markdownEscape(name) + "\n")
+	var parts []string
+	for _, part := range contact {
+		parts = append(parts, markdownSpan(part, markdownEscape))
+	}
+	if len(parts) > 0 {
+		w.WriteString("\n" + strings.Join(parts, " · ") + "\n")
+	}
+}
+
+func (w *markdownWriter) writeSection(title string) {
+	w.WriteString("\n## " + markdownEscape(title) + "\n")
+}
+
+// writeEntries writes each entry under its own heading, with the subtitle
+// and details in italics on one line
+func (w *markdownWriter) writeEntries(entries []entry) {
+	for _, e := range entries {
+		if title := cleanText(e.title); title != "" {
+			w.WriteString("\n### " + markdownEscape(title) + "\n")
+		}
+		var details []string
+		for _, line := range e.lines() {
+			if line.link != "" {
+				details = append(details, markdownSpan(line, markdownEscape))
+			} else {
+				details = append(details, "*"+markdownEscape(line.text)+"*")
+			}
+		}
+		if len(details) > 0 {
+			w.WriteString("\n" + strings.Join(details, " · ") + "\n")
+		}
+		w.writeBlocks(e.blocks())
+	}
+}
+
+// writeBlocks writes paragraphs separated by a blank line and consecutive
+// bullets as a list
+func (w *markdownWriter) writeBlocks(blocks []mdBlock) {
+	for i, block := range blocks {
+		if i == 0 || !block.bullet || !blocks[i-1].bullet {
+			w.WriteString("\n")
+		}
+		var b strings.Builder
+		for _, span := range block.spans {
+			b.WriteString(markdownSpan(span, markdownEscape))
+		}
+		if block.bullet {
+			w.WriteString("- " + b.String() + "\n")
+		} else {
+			w.WriteString(markdownParagraph(b.String()) + "\n")
+		}
+	}
+}
+
+func (w *markdownWriter) writeText(text string) {
+	w.WriteString("\n" + markdownParagraph(markdownEscape(text)) + "\n")
+}
+
+func (w *markdownWriter) writeList(items []string) {
+	w.WriteString("\n")
+	for _, item := range items {
+		w.WriteString("- " + markdownEscape(item) + "\n")
+	}
+}
+
+// markdownSpan returns a span as Markdown with its emphasis and link, the
+// text escaped by escape
+func markdownSpan(span mdSpan, escape func(string) string) string {
+	// Emphasis markers must touch the text, so surrounding spaces are kept
+	// outside of them
+	text := strings.TrimSpace(span.text)
+	if text == "" {
+		return span.text
+	}
+	lead := span.text[:strings.Index(span.text, text)]
+	trail := span.text[len(lead)+len(text):]
+
+	text = escape(text)
+	if span.italic {
+		text = "*" + text + "*"
+	}
+	if span.bold {
+		text = "**" + text + "**"
+	}
+	if span.link != "" {
+		text = "[" + text + "](" + strings.ReplaceAll(span.link, " ", "%20") + ")"
+	}
+	return lead + text + trail
+}
+
+// markdownEscape escapes the characters that could start Markdown or HTML
+// markup in user text
+func markdownEscape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if strings.ContainsRune("\\`*_[]<>#|~", r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// markdownParagraph escapes the start of a paragraph that would otherwise
+// begin an ordered list, such as "2020. A year of change", a bullet list,
+// a rule or a heading underline
+func markdownParagraph(text string) string {
+	digits := strings.IndexFunc(text, func(r rune) bool { return r < '0' || r > '9' })
+	if digits > 0 && (text[digits] == '.' || text[digits] == ')') {
+		return text[:digits] + "\\" + text[digits:]
+	}
+	if strings.HasPrefix(text, "-") || strings.HasPrefix(text, "+") || strings.HasPrefix(text, "=") {
+		return "\\" + text
+	}
+	return text
+}
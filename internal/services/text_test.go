@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"cv-generator/internal/models"
+)
+
+func TestMarkdownEscape(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"plain text", "plain text"},
+		{"C# & F#", `C\# & F\#`},
+		{"**not bold**", `\*\*not bold\*\*`},
+		{"snake_case", `snake\_case`},
+		{"<script>alert(1)</script>", `\<script\>alert(1)\</script\>`},
+		{"[x](https://evil.dev)", `\[x\](https://evil.dev)`},
+		{"a | b ~ c `d`", "a \\| b \\~ c \\`d\\`"},
+		{`back\slash`, `back\\slash`},
+		{"Zażółć", "Zażółć"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := markdownEscape(tt.input); got != tt.want {
+				t.Errorf("markdownEscape(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownParagraph(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"2020. A year of change", `2020\. A year of change`},
+		{"1) First", `1\) First`},
+		{"- not a bullet", `\- not a bullet`},
+		{"+ not a bullet", `\+ not a bullet`},
+		{"===", `\===`},
+		{"2020 was a year", "2020 was a year"},
+		{"Version 2.0", "Version 2.0"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := markdownParagraph(tt.input); got != tt.want {
+				t.Errorf("markdownParagraph(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownSpan(t *testing.T) {
+	tests := []struct {
+		name string
+		span mdSpan
+		want string
+	}{
+		{"plain", mdSpan{text: "text"}, "text"},
+		{"spaces outside markers", mdSpan{text: " bold ", bold: true}, " **bold** "},
+		{"bold italic", mdSpan{text: "both", bold: true, italic: true}, "***both***"},
+		{"link", mdSpan{text: "Go", link: "https://go.dev/a b"}, "[Go](https://go.dev/a%20b)"},
+		{"escaped link text", mdSpan{text: "[x]", link: "https://x.dev"}, `[\[x\]](https://x.dev)`},
+		{"only spaces", mdSpan{text: "  ", bold: true}, "  "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdownSpan(tt.span, markdownEscape); got != tt.want {
+				t.Errorf("markdownSpan(%+v) = %q, want %q", tt.span, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainSpan(t *testing.T) {
+	tests := []struct {
+		name string
+		span mdSpan
+		want string
+	}{
+		{"no link", mdSpan{text: "text"}, "text"},
+		{"link after label", mdSpan{text: "docs", link: "https://go.dev"}, "docs (https://go.dev)"},
+		{"label shows the link", mdSpan{text: "https://go.dev", link: "https://go.dev"}, "https://go.dev"},
+		{"email", mdSpan{text: "write me", link: "mailto:a@b.dev"}, "write me (a@b.dev)"},
+		{"phone", mdSpan{text: "+48 600", link: "tel:+48600"}, "+48 600"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := plainSpan(tt.span); got != tt.want {
+				t.Errorf("plainSpan(%+v) = %q, want %q", tt.span, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextGenerateCV(t *testing.T) {
+	cv := models.CV{
+		PersonalInfo: models.PersonalInfo{FullName: "Zofia <b>Nowak</b>", Email: "zofia@example.com", Summary: "1. Go"},
+		Experience: []models.Experience{{
+			Company: "Acme", Position: "Engineer", StartDate: "2020",
+			Description: "2020. A year\n\n- **Rust**",
+		}},
+		CustomSections: []models.CustomSection{{Title: "Talks & more", Content: "GopherCon"}},
+	}
+	tests := []struct {
+		name    string
+		service *TextService
+		want    []string
+	}{
+		{"plain", NewTextService(), []string{"Zofia <b>Nowak</b>\n", "Talks & more\n============\n", "1. Go\n", "2020. A year\n", "- Rust\n"}},
+		{"markdown", NewMarkdownService(), []string{`# Zofia \<b\>Nowak\</b\>`, "## Talks & more\n", `1\. Go`, `2020\. A year`, "- **Rust**"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := tt.service.GenerateCV(context.Background(), cv)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(out), want) {
+					t.Errorf("GenerateCV() = %q, want it to contain %q", out, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTextSectionTitles(t *testing.T) {
+	cv := longCV(1)
+	cv.Language = "es"
+	cv.Layout = []models.SectionLayout{{Section: models.SectionExperience, Title: "Berufserfahrung"}}
+	cv.CustomSections = []models.CustomSection{{Title: "Open source", Content: "cvgen"}}
+	tests := []struct {
+		name    string
+		service *TextService
+		want    []string
+	}{
+		{"plain", NewTextService(), []string{"\nBerufserfahrung\n===============\n", "\nOpen source\n===========\n"}},
+		{"markdown", NewMarkdownService(), []string{"\n## Berufserfahrung\n", "\n## Open source\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := tt.service.GenerateCV(context.Background(), cv)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(out), want) {
+					t.Errorf("GenerateCV() = %q, want it to contain %q", out, want)
+				}
+			}
+			for _, unwanted := range []string{"EXPERIENCIA", "BERUFSERFAHRUNG", "OPEN SOURCE"} {
+				if strings.Contains(string(out), unwanted) {
+					t.Errorf("GenerateCV() = %q, want no %q", out, unwanted)
+				}
+			}
+		})
+	}
+}
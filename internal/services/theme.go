@@ -0,0 +1,304 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cv-generator/internal/models"
+)
+
+// Color is an RGB color with components in the 0-255 range
+type Color struct {
+	R, G, B int
+}
+
+// ThemePalette holds the colors used by a theme
+type ThemePalette struct {
+	Text      Color // body text and item titles
+	LightText Color // contact details and dates
+	Separator Color // section separator lines
+	Accent    Color // name and section titles
+}
+
+// ThemeFonts holds the font family, styles and sizes (in points) of a theme
+type ThemeFonts struct {
+	Family            string
+	NameSize          float64
+	NameStyle         string
+	ContactSize       float64
+	SectionTitleSize  float64
+	SectionTitleStyle string
+	ItemTitleSize     float64
+	ItemTitleStyle    string
+	ItemSubtitleSize  float64
+	ItemSubtitleStyle string
+	BodySize          float64
+}
+
+// ThemeSpacing holds line heights, gaps and margins of a theme, in mm
+type ThemeSpacing struct {
+	Margin             float64
+	NameHeight         float64
+	ContactLineHeight  float64
+	SectionTitleHeight float64
+	BodyLineHeight     float64
+	ItemLineHeight     float64
+	AfterName          float64
+	AfterContact       float64
+	AfterHeader        float64
+	AfterSectionTitle  float64
+	BetweenItems       float64
+	AfterSection       float64
+}
+
+// SeparatorStyle controls how section titles are underlined
+type SeparatorStyle string
+
+const (
+	SeparatorLine   SeparatorStyle = "line"
+	SeparatorThick  SeparatorStyle = "thick"
+	SeparatorDouble SeparatorStyle = "double"
+	SeparatorNone   SeparatorStyle = "none"
+)
+
+// LevelStyle controls how language proficiency levels are shown
+type LevelStyle string
+
+const (
+	LevelText LevelStyle = "text" // "English — C1 (Advanced)"
+	LevelDots LevelStyle = "dots" // name followed by a six-dot scale
+)
+
+// ThemeSidebar enables the two-column layout. Widths are in mm measured
+// from the left edge of the page.
+type ThemeSidebar struct {
+	Width      float64 // sidebar width, including its padding
+	Padding    float64 // horizontal padding inside the sidebar
+	Gutter     float64 // space between the sidebar and the main column
+	Background *Color  // optional tint, nil for none
+}
+
+// ThemePage controls the running header and footer. Both are drawn inside
+// the top and bottom page margins, and only on CVs longer than one page.
+type ThemePage struct {
+	PageNumbers   bool    // "Page X of Y" footer
+	RunningHeader bool    // name and contact details on every page but the first
+	FontSize      float64 // header and footer text size, in points
+}
+
+// Theme describes every visual choice of the PDF layout
+type Theme struct {
+	Name      string
+	PageSize  string // gofpdf page size name, e.g. "A4" or "Letter"
+	Palette   ThemePalette
+	Fonts     ThemeFonts
+	Spacing   ThemeSpacing
+	Separator SeparatorStyle
+	Levels    LevelStyle    // language proficiency rendering
+	Page      ThemePage     // running header and footer
+	Sidebar   *ThemeSidebar // nil for the single-column layout
+	MinScale  float64       // smallest scale a CV with fitPages may be shrunk to
+}
+
+// DefaultThemeName is used when a CV does not select a theme
+const DefaultThemeName = "minimal"
+
+// minimalTheme is the original monochromatic Notion-style layout
+var minimalTheme = Theme{
+	Name:     "minimal",
+	PageSize: "A4",
+	Palette: ThemePalette{
+		Text:      Color{55, 53, 47},    // #37352f
+		LightText: Color{111, 111, 111}, // #6f6f6f
+		Separator: Color{227, 226, 224}, // #e3e2e0
+		Accent:    Color{55, 53, 47},    // #37352f
+	},
+	Fonts: ThemeFonts{
+		Family:            fontFamily,
+		NameSize:          18,
+		NameStyle:         "B",
+		ContactSize:       9,
+		SectionTitleSize:  10,
+		SectionTitleStyle: "B",
+		ItemTitleSize:     10,
+		ItemTitleStyle:    "B",
+		ItemSubtitleSize:  9,
+		ItemSubtitleStyle: "I",
+		BodySize:          10,
+	},
+	Spacing: ThemeSpacing{
+		Margin:             25,
+		NameHeight:         12,
+		ContactLineHeight:  5,
+		SectionTitleHeight: 6,
+		BodyLineHeight:     5,
+		ItemLineHeight:     4,
+		AfterName:          3,
+		AfterContact:       5,
+		AfterHeader:        8,
+		AfterSectionTitle:  3,
+		BetweenItems:       3,
+		AfterSection:       5,
+	},
+	Separator: SeparatorLine,
+	Levels:    LevelText,
+	Page: ThemePage{
+		PageNumbers:   true,
+		RunningHeader: true,
+		FontSize:      8,
+	},
+	MinScale: 0.8,
+}
+
+// themes is the registry of built-in themes, keyed by name
+var themes = map[string]Theme{
+	"minimal": minimalTheme,
+	"classic": func() Theme {
+		t := minimalTheme
+		t.Name = "classic"
+		t.PageSize = "Letter"
+		t.Palette = ThemePalette{
+			Text:      Color{0, 0, 0},
+			LightText: Color{80, 80, 80},
+			Separator: Color{0, 0, 0},
+			Accent:    Color{0, 0, 0},
+		}
+		t.Fonts.NameSize = 22
+		t.Fonts.SectionTitleSize = 11
+		t.Spacing.Margin = 20
+		t.Separator = SeparatorDouble
+		return t
+	}(),
+	"modern": func() Theme {
+		t := minimalTheme
+		t.Name = "modern"
+		t.Palette.Accent = Color{37, 99, 235} // #2563eb
+		t.Palette.Separator = Color{37, 99, 235}
+		t.Fonts.NameSize = 24
+		t.Fonts.SectionTitleSize = 11
+		t.Spacing.NameHeight = 14
+		t.Spacing.AfterSection = 6
+		t.Separator = SeparatorThick
+		t.Levels = LevelDots
+		return t
+	}(),
+	"compact": func() Theme {
+		t := minimalTheme
+		t.Name = "compact"
+		t.Fonts.NameSize = 15
+		t.Fonts.ContactSize = 8
+		t.Fonts.SectionTitleSize = 9
+		t.Fonts.ItemTitleSize = 9
+		t.Fonts.ItemSubtitleSize = 8
+		t.Fonts.BodySize = 9
+		t.Page.FontSize = 7
+		t.MinScale = 0.9 // already close to the smallest readable size
+		t.Spacing = ThemeSpacing{
+			Margin:             15,
+			NameHeight:         9,
+			ContactLineHeight:  4,
+			SectionTitleHeight: 5,
+			BodyLineHeight:     4,
+			ItemLineHeight:     3.6,
+			AfterName:          1.5,
+			AfterContact:       3,
+			AfterHeader:        5,
+			AfterSectionTitle:  2,
+			BetweenItems:       2,
+			AfterSection:       3,
+		}
+		return t
+	}(),
+	"sidebar": func() Theme {
+		t := minimalTheme
+		t.Name = "sidebar"
+		t.Fonts.NameSize = 20
+		t.Spacing.Margin = 18
+		t.Levels = LevelDots
+		t.Sidebar = &ThemeSidebar{
+			Width:      68,
+			Padding:    10,
+			Gutter:     10,
+			Background: &Color{247, 246, 243}, // #f7f6f3
+		}
+		return t
+	}(),
+}
+
+// scaled returns a copy of the theme with font sizes, line heights and
+// spacing multiplied by scale. Margins, the sidebar and the running header
+// keep their size.
+func (t Theme) scaled(scale float64) Theme {
+	f := &t.Fonts
+	for _, size := range []*float64{&f.NameSize, &f.ContactSize, &f.SectionTitleSize, &f.ItemTitleSize, &f.ItemSubtitleSize, &f.BodySize} {
+		*size *= scale
+	}
+	sp := &t.Spacing
+	for _, length := range []*float64{
+		&sp.NameHeight, &sp.ContactLineHeight, &sp.SectionTitleHeight, &sp.BodyLineHeight, &sp.ItemLineHeight,
+		&sp.AfterName, &sp.AfterContact, &sp.AfterHeader, &sp.AfterSectionTitle, &sp.BetweenItems, &sp.AfterSection,
+	} {
+		*length *= scale
+	}
+	return t
+}
+
+// GetTheme returns the built-in theme with the given name. An empty name
+// selects the default theme.
+func GetTheme(name string) (Theme, bool) {
+	if name == "" {
+		name = DefaultThemeName
+	}
+	theme, ok := themes[name]
+	return theme, ok
+}
+
+// ThemeNames lists the names of the built-in themes in alphabetical order
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// pageSizes are the gofpdf page size names a CV may select
+var pageSizes = []string{"A3", "A4", "A5", "Letter", "Legal"}
+
+// PageSizes lists the supported page size names
+func PageSizes() []string {
+	return append([]string(nil), pageSizes...)
+}
+
+// IsPageSize reports whether name is a supported page size
+func IsPageSize(name string) bool {
+	for _, size := range pageSizes {
+		if size == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateRenderOptions checks the CV fields whose allowed values are
+// defined by this package, such as the theme and page size
+func ValidateRenderOptions(cv models.CV) []models.Violation {
+	var violations []models.Violation
+	if _, ok := GetTheme(cv.Theme); !ok {
+		violations = append(violations, models.Violation{
+			Field:   "theme",
+			Code:    models.CodeUnsupported,
+			Message: fmt.Sprintf("must be one of %s", strings.Join(ThemeNames(), ", ")),
+		})
+	}
+	if cv.PageSize != "" && !IsPageSize(cv.PageSize) {
+		violations = append(violations, models.Violation{
+			Field:   "pageSize",
+			Code:    models.CodeUnsupported,
+			Message: fmt.Sprintf("must be one of %s", strings.Join(pageSizes, ", ")),
+		})
+	}
+	return violations
+}
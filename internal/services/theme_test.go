@@ -0,0 +1,89 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"cv-generator/internal/models"
+)
+
+func TestGetTheme(t *testing.T) {
+	tests := []struct {
+		name     string
+		want     string // empty when the theme does not exist
+		minScale float64
+	}{
+		{"", DefaultThemeName, 0.8},
+		{"minimal", "minimal", 0.8},
+		{"classic", "classic", 0.8},
+		{"modern", "modern", 0.8},
+		{"compact", "compact", 0.9},
+		{"sidebar", "sidebar", 0.8},
+		{"neon", "", 0},
+		{"Minimal", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			theme, ok := GetTheme(tt.name)
+			if ok != (tt.want != "") {
+				t.Fatalf("GetTheme(%q) ok = %v, want %v", tt.name, ok, !ok)
+			}
+			if !ok {
+				return
+			}
+			if theme.Name != tt.want || theme.MinScale != tt.minScale {
+				t.Errorf("GetTheme(%q) = %s with MinScale %v, want %s with %v", tt.name, theme.Name, theme.MinScale, tt.want, tt.minScale)
+			}
+			if theme.MinScale <= 0 || theme.MinScale > 1 {
+				t.Errorf("GetTheme(%q) MinScale = %v, want it in (0, 1]", tt.name, theme.MinScale)
+			}
+		})
+	}
+}
+
+func TestThemeNames(t *testing.T) {
+	want := []string{"classic", "compact", "minimal", "modern", "sidebar"}
+	if got := ThemeNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ThemeNames() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateRenderOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		cv     models.CV
+		fields []string
+	}{
+		{"defaults", models.CV{}, nil},
+		{"theme and page size", models.CV{Theme: "sidebar", PageSize: "Letter"}, nil},
+		{"unknown theme", models.CV{Theme: "neon"}, []string{"theme"}},
+		{"unknown page size", models.CV{PageSize: "a4"}, []string{"pageSize"}},
+		{"both", models.CV{Theme: "neon", PageSize: "B5"}, []string{"theme", "pageSize"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []string
+			for _, v := range ValidateRenderOptions(tt.cv) {
+				if v.Code != models.CodeUnsupported {
+					t.Errorf("ValidateRenderOptions() code = %q, want %q", v.Code, models.CodeUnsupported)
+				}
+				fields = append(fields, v.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("ValidateRenderOptions() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestThemeScaled(t *testing.T) {
+	theme := mustTheme(t, "sidebar")
+	scaled := theme.scaled(0.5)
+	if scaled.Fonts.BodySize != theme.Fonts.BodySize*0.5 || scaled.Spacing.BodyLineHeight != theme.Spacing.BodyLineHeight*0.5 {
+		t.Errorf("scaled(0.5) body = %v/%v, want half of %v/%v",
+			scaled.Fonts.BodySize, scaled.Spacing.BodyLineHeight, theme.Fonts.BodySize, theme.Spacing.BodyLineHeight)
+	}
+	if scaled.Spacing.Margin != theme.Spacing.Margin || scaled.Page.FontSize != theme.Page.FontSize || *scaled.Sidebar != *theme.Sidebar {
+		t.Errorf("scaled(0.5) changed the margins, running header or sidebar")
+	}
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"strings"
+
+	"cv-generator/internal/models"
+)
+
+// Translation maps for different languages
+var translations = map[string]map[string]string{
+	"en": {
+		// Section headers
+		"SUMMARY":        "SUMMARY",
+		"EXPERIENCE":     "EXPERIENCE",
+		"EDUCATION":      "EDUCATION",
+		"SKILLS":         "SKILLS",
+		"LANGUAGES":      "LANGUAGES",
+		"CONTACT":        "CONTACT",
+		"PROJECTS":       "PROJECTS",
+		"CERTIFICATIONS": "CERTIFICATIONS",
+		"AWARDS":         "AWARDS",
+		"PUBLICATIONS":   "PUBLICATIONS",
+		"VOLUNTEER":      "VOLUNTEER WORK",
+		// Common words
+		"Present":       "Present",
+		"at":            "at",
+		"Technologies":  "Technologies",
+		"Expires":       "Expires",
+		"Credential ID": "Credential ID",
+		"CV":            "Curriculum Vitae",
+		"Page %d of %d": "Page %d of %d",
+		// Europass headline and skill categories
+		"Personal statement":    "Personal statement",
+		"Communication skills":  "Communication skills",
+		"Organisational skills": "Organisational skills",
+		"Job-related skills":    "Job-related skills",
+		"Driving licence":       "Driving licence",
+		"Other skills":          "Other skills",
+		// Skill levels - from Spanish to English
+		"Básico":     "Basic",
+		"básico":     "Basic",
+		"Intermedio": "Intermediate",
+		"intermedio": "Intermediate",
+		"Avanzado":   "Advanced",
+		"avanzado":   "Advanced",
+		"Experto":    "Expert",
+		"experto":    "Expert",
+		// Already English skill levels (no translation needed)
+		"Basic":        "Basic",
+		"basic":        "Basic",
+		"Intermediate": "Intermediate",
+		"intermediate": "Intermediate",
+		"Advanced":     "Advanced",
+		"advanced":     "Advanced",
+		"Expert":       "Expert",
+		"expert":       "Expert",
+		// Language levels (CEFR)
+		"A1":     "Beginner",
+		"A2":     "Elementary",
+		"B1":     "Intermediate",
+		"B2":     "Upper intermediate",
+		"C1":     "Advanced",
+		"C2":     "Proficient",
+		"Native": "Native",
+	},
+	"es": {
+		// Section headers
+		"SUMMARY":        "RESUMEN",
+		"EXPERIENCE":     "EXPERIENCIA",
+		"EDUCATION":      "EDUCACIÓN",
+		"SKILLS":         "HABILIDADES",
+		"LANGUAGES":      "IDIOMAS",
+		"CONTACT":        "CONTACTO",
+		"PROJECTS":       "PROYECTOS",
+		"CERTIFICATIONS": "CERTIFICACIONES",
+		"AWARDS":         "PREMIOS",
+		"PUBLICATIONS":   "PUBLICACIONES",
+		"VOLUNTEER":      "VOLUNTARIADO",
+		// Common words
+		"Present":       "Presente",
+		"at":            "en",
+		"Technologies":  "Tecnologías",
+		"Expires":       "Vence",
+		"Credential ID": "ID de credencial",
+		"CV":            "Currículum Vitae",
+		"Page %d of %d": "Página %d de %d",
+		// Europass headline and skill categories
+		"Personal statement":    "Perfil personal",
+		"Communication skills":  "Competencias comunicativas",
+		"Organisational skills": "Competencias de organización",
+		"Job-related skills":    "Competencias relacionadas con el empleo",
+		"Driving licence":       "Permiso de conducir",
+		"Other skills":          "Otras competencias",
+		// Skill levels - from English to Spanish
+		"Basic":        "Básico",
+		"basic":        "Básico",
+		"Intermediate": "Intermedio",
+		"intermediate": "Intermedio",
+		"Advanced":     "Avanzado",
+		"advanced":     "Avanzado",
+		"Expert":       "Experto",
+		"expert":       "Experto",
+		// Already Spanish skill levels (no translation needed)
+		"Básico":     "Básico",
+		"básico":     "Básico",
+		"Intermedio": "Intermedio",
+		"intermedio": "Intermedio",
+		"Avanzado":   "Avanzado",
+		"avanzado":   "Avanzado",
+		"Experto":    "Experto",
+		"experto":    "experto",
+		// Language levels (CEFR)
+		"A1":     "Principiante",
+		"A2":     "Elemental",
+		"B1":     "Intermedio",
+		"B2":     "Intermedio alto",
+		"C1":     "Avanzado",
+		"C2":     "Maestría",
+		"Native": "Nativo",
+	},
+}
+
+// translate converts text based on the target language. It is shared by all
+// renderers so every output format uses the same wording.
+func translate(text, targetLang string) string {
+	if targetLang == "" {
+		targetLang = "en" // default to English
+	}
+
+	if langMap, exists := translations[targetLang]; exists {
+		if translated, exists := langMap[text]; exists {
+			return translated
+		}
+	}
+
+	return text // fallback to original text if no translation found
+}
+
+// languageLevelLabel describes a models.Language level in the target
+// language, e.g. "C1 (Advanced)" or "Nativo". Unknown levels are returned
+// unchanged.
+func languageLevelLabel(level, targetLang string) string {
+	if level == "" || level == models.LevelNative || models.LevelRank(level) == 0 {
+		return translate(level, targetLang)
+	}
+	return level + " (" + translate(level, targetLang) + ")"
+}
+
+// sectionTitle returns the title override from the CV layout as written, or
+// else the translated default title of the section
+func sectionTitle(layout models.SectionLayout, targetLang string) string {
+	if layout.Title != "" {
+		return layout.Title
+	}
+	return translate(strings.ToUpper(layout.Section), targetLang)
+}
@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"cv-generator/internal/models"
+)
+
+// ErrNotFound is returned when a CV does not exist or belongs to another owner
+var ErrNotFound = errors.New("cv not found")
+
+// CVRepository persists CV documents. Every operation is scoped to an owner:
+// a CV stored by one owner is reported as ErrNotFound to any other.
+type CVRepository interface {
+	// Create assigns a new ID, sets the owner and both timestamps, and stores cv
+	Create(ctx context.Context, owner string, cv *models.CV) error
+	// Get returns the CV with the given ID
+	Get(ctx context.Context, owner, id string) (models.CV, error)
+	// List returns the owner's CVs, most recently updated first
+	List(ctx context.Context, owner string) ([]models.CV, error)
+	// Update replaces the stored document, keeping its ID, owner and
+	// CreatedAt, and refreshes UpdatedAt
+	Update(ctx context.Context, owner string, cv *models.CV) error
+	// Delete removes the CV with the given ID
+	Delete(ctx context.Context, owner, id string) error
+	// Close releases the underlying resources
+	Close() error
+}
@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"cv-generator/internal/models"
+
+	"github.com/google/uuid"
+	_ "modernc.org/sqlite" // pure-Go SQLite driver, no cgo required
+)
+
+// schema creates the cvs table. The CV itself is stored as a JSON document so
+// new model fields need no migration; the columns hold what is queried on.
+const schema = `
+CREATE TABLE IF NOT EXISTS cvs (
+	id         TEXT PRIMARY KEY,
+	owner      TEXT NOT NULL,
+	document   TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS cvs_owner_updated ON cvs (owner, updated_at);
+`
+
+// SQLiteRepository is a CVRepository backed by a SQLite database file
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRepository opens (creating if needed) the database at path and
+// applies the schema. Use ":memory:" for a throwaway database.
+func NewSQLiteRepository(path string) (*SQLiteRepository, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	// SQLite allows a single writer; serializing connections avoids
+	// SQLITE_BUSY errors and keeps ":memory:" databases shared
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("apply schema: %w", err)
+	}
+	return &SQLiteRepository{db: db}, nil
+}
+
+func (r *SQLiteRepository) Create(ctx context.Context, owner string, cv *models.CV) error {
+	now := time.Now().UTC()
+	cv.ID = uuid.NewString()
+	cv.Owner = owner
+	cv.CreatedAt = now
+	cv.UpdatedAt = now
+
+	document, err := json.Marshal(cv)
+	if err != nil {
+		return fmt.Errorf("encode cv: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx,
+		`INSERT INTO cvs (id, owner, document, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		cv.ID, owner, string(document), now, now)
+	if err != nil {
+		return fmt.Errorf("insert cv: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) Get(ctx context.Context, owner, id string) (models.CV, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT document FROM cvs WHERE id = ? AND owner = ?`, id, owner)
+	return scanCV(row)
+}
+
+func (r *SQLiteRepository) List(ctx context.Context, owner string) ([]models.CV, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT document FROM cvs WHERE owner = ? ORDER BY updated_at DESC`, owner)
+	if err != nil {
+		return nil, fmt.Errorf("list cvs: %w", err)
+	}
+	defer rows.Close()
+
+	cvs := []models.CV{}
+	for rows.Next() {
+		cv, err := scanCV(rows)
+		if err != nil {
+			return nil, err
+		}
+		cvs = append(cvs, cv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list cvs: %w", err)
+	}
+	return cvs, nil
+}
+
+func (r *SQLiteRepository) Update(ctx context.Context, owner string, cv *models.CV) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin update: %w", err)
+	}
+	defer tx.Rollback()
+
+	stored, err := scanCV(tx.QueryRowContext(ctx, `SELECT document FROM cvs WHERE id = ? AND owner = ?`, cv.ID, owner))
+	if err != nil {
+		return err
+	}
+
+	cv.Owner = stored.Owner
+	cv.CreatedAt = stored.CreatedAt
+	cv.UpdatedAt = time.Now().UTC()
+
+	document, err := json.Marshal(cv)
+	if err != nil {
+		return fmt.Errorf("encode cv: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE cvs SET document = ?, updated_at = ? WHERE id = ? AND owner = ?`,
+		string(document), cv.UpdatedAt, cv.ID, owner); err != nil {
+		return fmt.Errorf("update cv: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit update: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) Delete(ctx context.Context, owner, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM cvs WHERE id = ? AND owner = ?`, id, owner)
+	if err != nil {
+		return fmt.Errorf("delete cv: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) Close() error {
+	return r.db.Close()
+}
+
+// scanCV decodes the JSON document in the current row
+func scanCV(row interface{ Scan(dest ...any) error }) (models.CV, error) {
+	var cv models.CV
+	var document string
+	if err := row.Scan(&document); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return cv, ErrNotFound
+		}
+		return cv, fmt.Errorf("read cv: %w", err)
+	}
+	if err := json.Unmarshal([]byte(document), &cv); err != nil {
+		return cv, fmt.Errorf("decode cv: %w", err)
+	}
+	return cv, nil
+}
@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cv-generator/internal/models"
+)
+
+// newTestRepository returns a repository on a throwaway in-memory database
+func newTestRepository(t *testing.T) *SQLiteRepository {
+	t.Helper()
+	repo, err := NewSQLiteRepository(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+// createCV stores a CV with the given name for owner
+func createCV(t *testing.T, repo *SQLiteRepository, owner, name string) models.CV {
+	t.Helper()
+	cv := models.CV{PersonalInfo: models.PersonalInfo{FullName: name}}
+	if err := repo.Create(context.Background(), owner, &cv); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return cv
+}
+
+func TestCreateGet(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	created := createCV(t, repo, "alice", "Zofia Nowak")
+	if created.ID == "" || created.Owner != "alice" || created.CreatedAt.IsZero() || !created.UpdatedAt.Equal(created.CreatedAt) {
+		t.Fatalf("Create() = %+v, want an ID, the owner and equal timestamps", created)
+	}
+
+	got, err := repo.Get(ctx, "alice", created.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.ID != created.ID || got.Owner != "alice" || got.PersonalInfo.FullName != "Zofia Nowak" || !got.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("Get() = %+v, want %+v", got, created)
+	}
+
+	if _, err := repo.Get(ctx, "alice", "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() of a missing ID error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestList(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	first := createCV(t, repo, "alice", "First")
+	createCV(t, repo, "alice", "Second")
+	createCV(t, repo, "bob", "Other owner")
+
+	// Updating the first CV moves it to the top
+	if err := repo.Update(ctx, "alice", &first); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	tests := []struct {
+		owner string
+		want  []string
+	}{
+		{"alice", []string{"First", "Second"}},
+		{"bob", []string{"Other owner"}},
+		{"carol", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.owner, func(t *testing.T) {
+			cvs, err := repo.List(ctx, tt.owner)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if cvs == nil {
+				t.Errorf("List() = nil, want an empty list")
+			}
+			if len(cvs) != len(tt.want) {
+				t.Fatalf("List() = %d CVs, want %d", len(cvs), len(tt.want))
+			}
+			for i, cv := range cvs {
+				if cv.PersonalInfo.FullName != tt.want[i] || cv.Owner != tt.owner {
+					t.Errorf("List()[%d] = %s owned by %s, want %s owned by %s", i, cv.PersonalInfo.FullName, cv.Owner, tt.want[i], tt.owner)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	created := createCV(t, repo, "alice", "Zofia Nowak")
+
+	// The client may send any owner and timestamps; the stored ones win
+	update := created
+	update.PersonalInfo.FullName = "Zofia Kowalska"
+	update.Owner = "mallory"
+	update.CreatedAt = time.Time{}
+	if err := repo.Update(ctx, "alice", &update); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if update.Owner != "alice" || !update.CreatedAt.Equal(created.CreatedAt) || !update.UpdatedAt.After(created.UpdatedAt) {
+		t.Errorf("Update() = %+v, want owner, CreatedAt kept and UpdatedAt refreshed", update)
+	}
+
+	got, err := repo.Get(ctx, "alice", created.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.PersonalInfo.FullName != "Zofia Kowalska" || got.Owner != "alice" || !got.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("Get() after Update() = %+v", got)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	created := createCV(t, repo, "alice", "Zofia Nowak")
+
+	if err := repo.Delete(ctx, "alice", created.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.Get(ctx, "alice", created.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want ErrNotFound", err)
+	}
+	if err := repo.Delete(ctx, "alice", created.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestOtherOwner(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	created := createCV(t, repo, "alice", "Zofia Nowak")
+
+	tests := []struct {
+		name string
+		op   func() error
+	}{
+		{"get", func() error {
+			_, err := repo.Get(ctx, "bob", created.ID)
+			return err
+		}},
+		{"update", func() error {
+			cv := created
+			cv.PersonalInfo.FullName = "Taken over"
+			return repo.Update(ctx, "bob", &cv)
+		}},
+		{"delete", func() error {
+			return repo.Delete(ctx, "bob", created.ID)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.op(); !errors.Is(err, ErrNotFound) {
+				t.Errorf("%s by another owner error = %v, want ErrNotFound", tt.name, err)
+			}
+		})
+	}
+
+	// The CV is untouched
+	got, err := repo.Get(ctx, "alice", created.ID)
+	if err != nil || got.PersonalInfo.FullName != "Zofia Nowak" {
+		t.Errorf("Get() by the owner = %+v, %v, want the original CV", got, err)
+	}
+}
@@ -14,7 +14,8 @@ document.addEventListener('DOMContentLoaded', function () {
 
     // Setup form submission
     document.getElementById('cv-form').addEventListener('submit', function (e) {
-        updateHiddenInputs();
+        e.preventDefault();
+        submitCV(this.action);
     });
 });
 
@@ -28,7 +29,8 @@ function addExperience() {
         position: '',
         startDate: '',
         endDate: '',
-        description: ''
+        description: '',
+        highlights: []
     });
 
     const itemHtml = `
@@ -46,26 +48,32 @@ function addExperience() {
                 <div class="form-group">
                     <label>Empresa *</label>
                     <input type="text" required placeholder="Nombre de la empresa" 
-                           onchange="updateExperience(${index}, 'company', this.value)">
+                           data-field="company" onchange="updateExperience(${index}, 'company', this.value)">
                 </div>
                 <div class="form-group">
                     <label>Cargo *</label>
                     <input type="text" required placeholder="Tu cargo o posición" 
-                           onchange="updateExperience(${index}, 'position', this.value)">
+                           data-field="position" onchange="updateExperience(${index}, 'position', this.value)">
                 </div>
                 <div class="form-group">
                     <label>Fecha de Inicio</label>
-                    <input type="month" onchange="updateExperience(${index}, 'startDate', this.value)">
+                    <input type="month" data-field="startDate" onchange="updateExperience(${index}, 'startDate', this.value)">
                 </div>
                 <div class="form-group">
                     <label>Fecha de Fin</label>
-                    <input type="month" onchange="updateExperience(${index}, 'endDate', this.value)">
+                    <input type="month" data-field="endDate" onchange="updateExperience(${index}, 'endDate', this.value)">
                     <small style="color: var(--text-tertiary); font-size: 12px;">Deja vacío si es tu trabajo actual</small>
                 </div>
                 <div class="form-group full-width">
                     <label>Descripción</label>
                     <textarea rows="3" placeholder="Describe tus responsabilidades y logros..." 
-                              onchange="updateExperience(${index}, 'description', this.value)"></textarea>
+                              data-field="description" onchange="updateExperience(${index}, 'description', this.value)"></textarea>
+                </div>
+                <div class="form-group full-width">
+                    <label>Logros</label>
+                    <textarea rows="3" placeholder="Un logro por línea, se mostrarán como viñetas" 
+                              data-field="highlights" onchange="updateExperience(${index}, 'highlights', toLines(this.value))"></textarea>
+                    <small style="color: var(--text-tertiary); font-size: 12px;">Admite **negrita**, *cursiva* y [enlaces](https://...)</small>
                 </div>
             </div>
         </div>
@@ -80,6 +88,11 @@ function updateExperience(index, field, value) {
     }
 }
 
+// toLines splits a textarea into its non-empty lines
+function toLines(value) {
+    return value.split('\n').map(line => line.trim()).filter(line => line !== '');
+}
+
 function removeExperience(index) {
     const container = document.getElementById('experience-container');
     const item = container.querySelector(`[data-index="${index}"]`);
@@ -141,26 +154,26 @@ function addEducation() {
                 <div class="form-group">
                     <label>Institución *</label>
                     <input type="text" required placeholder="Universidad, instituto, etc." 
-                           onchange="updateEducation(${index}, 'institution', this.value)">
+                           data-field="institution" onchange="updateEducation(${index}, 'institution', this.value)">
                 </div>
                 <div class="form-group">
                     <label>Título/Grado *</label>
                     <input type="text" required placeholder="Carrera, certificación, etc." 
-                           onchange="updateEducation(${index}, 'degree', this.value)">
+                           data-field="degree" onchange="updateEducation(${index}, 'degree', this.value)">
                 </div>
                 <div class="form-group">
                     <label>Fecha de Inicio</label>
-                    <input type="month" onchange="updateEducation(${index}, 'startDate', this.value)">
+                    <input type="month" data-field="startDate" onchange="updateEducation(${index}, 'startDate', this.value)">
                 </div>
                 <div class="form-group">
                     <label>Fecha de Fin</label>
-                    <input type="month" onchange="updateEducation(${index}, 'endDate', this.value)">
+                    <input type="month" data-field="endDate" onchange="updateEducation(${index}, 'endDate', this.value)">
                     <small style="color: var(--text-tertiary); font-size: 12px;">Deja vacío si aún estudias</small>
                 </div>
                 <div class="form-group full-width">
                     <label>Descripción</label>
                     <textarea rows="2" placeholder="Menciones honoríficas, proyectos relevantes..." 
-                              onchange="updateEducation(${index}, 'description', this.value)"></textarea>
+                              data-field="description" onchange="updateEducation(${index}, 'description', this.value)"></textarea>
                 </div>
             </div>
         </div>
@@ -220,8 +233,8 @@ function addSkill() {
     const itemHtml = `
         <div class="skill-item" data-index="${index}">
             <input type="text" placeholder="Habilidad (ej: JavaScript, Liderazgo)" 
-                   onchange="updateSkill(${index}, 'name', this.value)">
-            <select class="skill-level" onchange="updateSkill(${index}, 'level', this.value)">
+                   data-field="name" onchange="updateSkill(${index}, 'name', this.value)">
+            <select class="skill-level" data-field="level" onchange="updateSkill(${index}, 'level', this.value)">
                 <option value="">Nivel</option>
                 <option value="Básico">Básico</option>
                 <option value="Intermedio">Intermedio</option>
@@ -279,12 +292,22 @@ function addLanguage() {
     const container = document.getElementById('languages-container');
     const index = languagesData.length;
 
-    languagesData.push('');
+    languagesData.push({
+        name: '',
+        level: '',
+        certificate: ''
+    });
 
+    const levels = i18n.t('languages.levels');
     const itemHtml = `
         <div class="language-item" data-index="${index}">
-            <input type="text" placeholder="Idioma (ej: Español - Nativo, Inglés - Avanzado)" 
-                   onchange="updateLanguage(${index}, this.value)">
+            <input type="text" placeholder="${i18n.t('placeholders.language')}" data-i18n-placeholder="placeholders.language"
+                   data-field="name" onchange="updateLanguage(${index}, 'name', this.value)">
+            <select class="language-level" data-field="level" onchange="updateLanguage(${index}, 'level', this.value)">
+                ${Object.keys(levels).map(value => `<option value="${value}">${levels[value]}</option>`).join('')}
+            </select>
+            <input type="text" placeholder="${i18n.t('placeholders.certificate')}" data-i18n-placeholder="placeholders.certificate"
+                   data-field="certificate" onchange="updateLanguage(${index}, 'certificate', this.value)">
             <button type="button" class="btn btn-danger" onclick="removeLanguage(${index})">
                 <i class="fas fa-trash"></i>
             </button>
@@ -294,8 +317,10 @@ function addLanguage() {
     container.insertAdjacentHTML('beforeend', itemHtml);
 }
 
-function updateLanguage(index, value) {
-    languagesData[index] = value;
+function updateLanguage(index, field, value) {
+    if (languagesData[index]) {
+        languagesData[index][field] = value;
+    }
 }
 
 function removeLanguage(index) {
@@ -314,10 +339,13 @@ function updateLanguageIndices() {
     items.forEach((item, newIndex) => {
         item.setAttribute('data-index', newIndex);
 
-        const input = item.querySelector('input');
-        if (input) {
-            input.setAttribute('onchange', `updateLanguage(${newIndex}, this.value)`);
-        }
+        const inputs = item.querySelectorAll('input, select');
+        inputs.forEach(input => {
+            const onchange = input.getAttribute('onchange');
+            if (onchange) {
+                input.setAttribute('onchange', onchange.replace(/updateLanguage\(\d+,/, `updateLanguage(${newIndex},`));
+            }
+        });
 
         const removeBtn = item.querySelector('.btn-danger');
         if (removeBtn) {
@@ -326,13 +354,31 @@ function updateLanguageIndices() {
     });
 }
 
+// Positions of the submitted entries in the form, so server-side errors
+// such as "experience[0].endDate" can be mapped back to the right item
+let submittedIndices = {};
+
+// Keep the entries that pass the filter and remember their form positions
+function filterEntries(section, data, keep) {
+    const indices = [];
+    const entries = data.filter((entry, index) => {
+        if (keep(entry)) {
+            indices.push(index);
+            return true;
+        }
+        return false;
+    });
+    submittedIndices[section] = indices;
+    return entries;
+}
+
 // Update hidden inputs before form submission
 function updateHiddenInputs() {
     // Filter out empty entries
-    const filteredExperience = experienceData.filter(exp => exp.company && exp.position);
-    const filteredEducation = educationData.filter(edu => edu.institution && edu.degree);
-    const filteredSkills = skillsData.filter(skill => skill.name);
-    const filteredLanguages = languagesData.filter(lang => lang.trim());
+    const filteredExperience = filterEntries('experience', experienceData, exp => exp.company && exp.position);
+    const filteredEducation = filterEntries('education', educationData, edu => edu.institution && edu.degree);
+    const filteredSkills = filterEntries('skills', skillsData, skill => skill.name);
+    const filteredLanguages = filterEntries('languages', languagesData, lang => lang.name.trim());
 
     document.getElementById('experience-data').value = JSON.stringify(filteredExperience);
     document.getElementById('education-data').value = JSON.stringify(filteredEducation);
@@ -340,158 +386,194 @@ function updateHiddenInputs() {
     document.getElementById('languages-data').value = JSON.stringify(filteredLanguages);
 }
 
-// Preview functionality
-function previewCV() {
+// Post the form to url with the current UI language. Validation errors are
+// shown next to the offending inputs and null is returned.
+async function postCV(url) {
     updateHiddenInputs();
+    clearValidationErrors();
 
-    const formData = new FormData(document.getElementById('cv-form'));
-    const personalInfo = {
-        fullName: formData.get('fullName'),
-        email: formData.get('email'),
-        phone: formData.get('phone'),
-        location: formData.get('location'),
-        linkedin: formData.get('linkedin'),
-        github: formData.get('github'),
-        website: formData.get('website'),
-        summary: formData.get('summary')
-    };
-
-    const experience = JSON.parse(formData.get('experience') || '[]');
-    const education = JSON.parse(formData.get('education') || '[]');
-    const skills = JSON.parse(formData.get('skills') || '[]');
-    const languages = JSON.parse(formData.get('languages') || '[]');
-
-    generatePreview(personalInfo, experience, education, skills, languages);
-    showModal();
+    const form = document.getElementById('cv-form');
+    const formData = new FormData(form);
+    formData.set('language', i18n.currentLang);
+
+    const response = await fetch(url, { method: 'POST', body: formData });
+    if (!response.ok) {
+        const body = await response.json().catch(() => ({}));
+        showValidationErrors(body.details || [], body.error);
+        return null;
+    }
+    return response;
 }
 
-function generatePreview(personalInfo, experience, education, skills, languages) {
-    const previewContent = document.getElementById('preview-content');
+// Submit the form to url in the background so validation errors can be
+// shown next to the offending inputs instead of replacing the page
+async function submitCV(url) {
+    const response = await postCV(url);
+    if (!response) {
+        return;
+    }
 
-    let html = `
-        <div class="cv-preview-container">
-            <div class="cv-preview">
-                <div class="cv-header">
-                    <div class="cv-name">${personalInfo.fullName || 'Tu Nombre'}</div>
-    `;
+    const blob = await response.blob();
+    const disposition = response.headers.get('Content-Disposition') || '';
+    const match = disposition.match(/filename="([^"]+)"/);
+
+    const link = document.createElement('a');
+    link.href = URL.createObjectURL(blob);
+    link.download = match ? match[1] : 'CV.pdf';
+    document.body.appendChild(link);
+    link.click();
+    link.remove();
+    URL.revokeObjectURL(link.href);
+}
 
-    // Contact information - exact PDF format
-    const contacts = [];
-    if (personalInfo.email) contacts.push(personalInfo.email);
-    if (personalInfo.phone) contacts.push(personalInfo.phone);
-    if (personalInfo.location) contacts.push(personalInfo.location);
-    if (personalInfo.linkedin) contacts.push(`LinkedIn: ${personalInfo.linkedin}`);
-    if (personalInfo.github) contacts.push(`GitHub: ${personalInfo.github}`);
-    if (personalInfo.website) contacts.push(`Website: ${personalInfo.website}`);
-
-    if (contacts.length > 0) {
-        html += `<div class="cv-contact">${contacts.join(' | ')}</div>`;
-    }
+// Download the CV as an editable Word document
+function exportDOCX() {
+    submitCV('/generate?format=docx');
+}
 
-    html += `<hr class="cv-separator">`;
-    html += `</div>`; // Close cv-header
+// Prefill the form from the ZIP archive of a LinkedIn data export. Data
+// that could not be imported is listed once the form is filled.
+async function importLinkedIn(input) {
+    const file = input.files[0];
+    input.value = '';
+    if (!file) {
+        return;
+    }
+    clearValidationErrors();
+
+    const formData = new FormData();
+    formData.append('file', file);
+    const response = await fetch('/api/v1/import/linkedin', { method: 'POST', body: formData });
+    const body = await response.json().catch(() => ({}));
+    if (!response.ok) {
+        showValidationErrors(body.details || [], body.error);
+        return;
+    }
 
-    // Summary section - exact PDF format
-    if (personalInfo.summary) {
-        html += `
-            <div class="cv-section">
-                <h2 class="cv-section-title">SUMMARY</h2>
-                <div class="cv-section-content">${personalInfo.summary}</div>
-            </div>
-        `;
+    loadCV(body.cv);
+    if (body.warnings.length > 0) {
+        alert([i18n.t('import.warnings'), ...body.warnings.map(w => `${w.field}: ${w.message}`)].join('\n'));
     }
+}
 
-    // Experience section - exact PDF format
-    if (experience.length > 0) {
-        html += `
-            <div class="cv-section">
-                <h2 class="cv-section-title">EXPERIENCE</h2>
-        `;
-        experience.forEach(exp => {
-            html += `
-                <div class="cv-item">
-                    <h3 class="cv-item-title">${exp.position} at ${exp.company}</h3>
-            `;
-
-            // Date formatting exactly like PDF
-            let dateRange = '';
-            if (exp.startDate && exp.endDate) {
-                dateRange = `${exp.startDate} - ${exp.endDate}`;
-            } else if (exp.startDate) {
-                dateRange = `${exp.startDate} - Present`;
-            }
+// Replace the form contents with a CV returned by an import endpoint
+function loadCV(cv) {
+    const info = cv.personalInfo || {};
+    ['fullName', 'email', 'phone', 'location', 'linkedin', 'github', 'website', 'summary'].forEach(field => {
+        document.getElementById(field).value = info[field] || '';
+    });
 
-            if (dateRange) {
-                html += `<div class="cv-item-dates">${dateRange}</div>`;
-            }
+    loadEntries('experience', experienceData, addExperience, cv.experience);
+    loadEntries('education', educationData, addEducation, cv.education);
+    loadEntries('skills', skillsData, addSkill, cv.skills);
+    loadEntries('languages', languagesData, addLanguage, cv.languages);
+}
 
-            if (exp.description) {
-                html += `<div class="cv-item-description">${exp.description}</div>`;
+// Rebuild the items of a section from entries, filling both the inputs and
+// the section data. An empty section keeps one blank item, as on load.
+function loadEntries(section, data, add, entries) {
+    document.getElementById(`${section}-container`).innerHTML = '';
+    data.length = 0;
+
+    (entries && entries.length > 0 ? entries : [{}]).forEach((entry, index) => {
+        add();
+        const item = document.querySelector(`#${section}-container [data-index="${index}"]`);
+        Object.keys(data[index]).forEach(field => {
+            if (entry[field] === undefined || entry[field] === null) {
+                return;
+            }
+            data[index][field] = entry[field];
+            const input = item.querySelector(`[data-field="${field}"]`);
+            if (input) {
+                input.value = Array.isArray(entry[field]) ? entry[field].join('\n') : entry[field];
             }
-
-            html += `</div>`;
         });
-        html += `</div>`; // Close experience section
+    });
+}
+
+// Find the input for a JSON path such as "personalInfo.email" or "experience[2].endDate"
+function findFieldInput(path) {
+    const personal = path.match(/^personalInfo\.(\w+)$/);
+    if (personal) {
+        return document.getElementById(personal[1]);
     }
 
-    // Education section - exact PDF format
-    if (education.length > 0) {
-        html += `
-            <div class="cv-section">
-                <h2 class="cv-section-title">EDUCATION</h2>
-        `;
-        education.forEach(edu => {
-            html += `
-                <div class="cv-item">
-                    <h3 class="cv-item-title">${edu.degree} - ${edu.institution}</h3>
-            `;
-
-            // Date formatting exactly like PDF
-            let dateRange = '';
-            if (edu.startDate && edu.endDate) {
-                dateRange = `${edu.startDate} - ${edu.endDate}`;
-            } else if (edu.startDate) {
-                dateRange = `${edu.startDate} - Present`;
-            }
+    const entry = path.match(/^(\w+)\[(\d+)\](?:\.(\w+))?$/);
+    if (!entry) {
+        return null;
+    }
 
-            if (dateRange) {
-                html += `<div class="cv-item-dates">${dateRange}</div>`;
-            }
+    const [, section, submittedIndex, field] = entry;
+    const indices = submittedIndices[section] || [];
+    const formIndex = indices[Number(submittedIndex)];
+    const container = document.getElementById(`${section}-container`);
+    const item = container && container.querySelector(`[data-index="${formIndex}"]`);
+    if (!item) {
+        return null;
+    }
 
-            if (edu.description) {
-                html += `<div class="cv-item-description">${edu.description}</div>`;
-            }
+    return field ? item.querySelector(`[data-field="${field}"]`) : item.querySelector('input');
+}
 
-            html += `</div>`;
-        });
-        html += `</div>`; // Close education section
+function showValidationErrors(details, fallbackMessage) {
+    const unplaced = [];
+
+    details.forEach(detail => {
+        const message = i18n.t(`validation.${detail.code}`) !== `validation.${detail.code}`
+            ? i18n.t(`validation.${detail.code}`)
+            : detail.message;
+        const input = detail.field ? findFieldInput(detail.field) : null;
+        if (!input) {
+            unplaced.push(detail.field ? `${detail.field}: ${message}` : message);
+            return;
+        }
+
+        input.classList.add('input-error');
+        const error = document.createElement('small');
+        error.className = 'field-error';
+        error.textContent = message;
+        input.insertAdjacentElement('afterend', error);
+    });
+
+    const firstError = document.querySelector('.input-error');
+    if (firstError) {
+        firstError.focus();
     }
 
-    // Skills section - exact PDF format
-    if (skills.length > 0) {
-        html += `
-            <div class="cv-section">
-                <h2 class="cv-section-title">SKILLS</h2>
-        `;
-        const skillTexts = skills.map(skill =>
-            skill.level ? `${skill.name} (${skill.level})` : skill.name
-        );
-        html += `<div class="cv-skills-content">${skillTexts.join(' • ')}</div>`;
-        html += `</div>`; // Close skills section
+    if (unplaced.length > 0 || details.length === 0) {
+        alert([fallbackMessage || i18n.t('validation.failed'), ...unplaced].join('\n'));
     }
+}
 
-    // Languages section - exact PDF format
-    if (languages.length > 0) {
-        html += `
-            <div class="cv-section">
-                <h2 class="cv-section-title">LANGUAGES</h2>
-                <div class="cv-languages-content">${languages.join(' • ')}</div>
-            </div>
-        `;
+function clearValidationErrors() {
+    document.querySelectorAll('.field-error').forEach(error => error.remove());
+    document.querySelectorAll('.input-error').forEach(input => input.classList.remove('input-error'));
+}
+
+// Preview functionality: the server renders the same PDF that is downloaded
+// and it is displayed inline, so the preview cannot drift from the export
+let previewURL = null;
+
+async function previewCV() {
+    const response = await postCV('/preview?format=pdf');
+    if (!response) {
+        return;
+    }
+
+    if (previewURL) {
+        URL.revokeObjectURL(previewURL);
     }
+    previewURL = URL.createObjectURL(await response.blob());
 
-    html += `</div></div>`; // Close cv-preview and cv-preview-container
-    previewContent.innerHTML = html;
+    const previewContent = document.getElementById('preview-content');
+    previewContent.innerHTML = '';
+    const frame = document.createElement('iframe');
+    frame.className = 'cv-preview-frame';
+    frame.title = i18n.t('preview.title');
+    frame.src = previewURL;
+    previewContent.appendChild(frame);
+
+    showModal();
 }
 
 function showModal() {
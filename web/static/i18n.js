@@ -10,8 +10,13 @@ const i18n = {
             actions: {
                 preview: 'Vista Previa',
                 export: 'Exportar PDF',
+                exportDocx: 'Exportar Word',
+                importLinkedIn: 'Importar LinkedIn',
                 add: 'Agregar',
-                remove: 'Eliminar'
+                remove: 'Eliminar',
+                fitNone: 'Sin ajustar',
+                fitOne: 'Ajustar a 1 página',
+                fitTwo: 'Ajustar a 2 páginas'
             },
             sections: {
                 personal: 'Información Personal',
@@ -55,11 +60,15 @@ const i18n = {
                 institution: 'Universidad, instituto, etc.',
                 degree: 'Carrera, certificación, etc.',
                 skillName: 'Habilidad (ej: JavaScript, Liderazgo)',
-                language: 'Idioma (ej: Español - Nativo, Inglés - Avanzado)'
+                language: 'Idioma (ej: Inglés)',
+                certificate: 'Certificado (ej: DELE C1, TOEFL)'
             },
             preview: {
                 title: 'Vista Previa del CV'
             },
+            import: {
+                warnings: 'CV importado. Estos datos no se han podido importar:'
+            },
             experience: {
                 title: 'Experiencia',
                 number: 'Experiencia'
@@ -77,10 +86,39 @@ const i18n = {
                     'Experto': 'Experto'
                 }
             },
+            languages: {
+                levels: {
+                    '': 'Nivel',
+                    'A1': 'A1 - Principiante',
+                    'A2': 'A2 - Elemental',
+                    'B1': 'B1 - Intermedio',
+                    'B2': 'B2 - Intermedio alto',
+                    'C1': 'C1 - Avanzado',
+                    'C2': 'C2 - Maestría',
+                    'Native': 'Nativo'
+                }
+            },
             notes: {
                 currentJob: 'Deja vacío si es tu trabajo actual',
                 currentStudy: 'Deja vacío si aún estudias',
                 achievements: 'Menciones honoríficas, proyectos relevantes...'
+            },
+            validation: {
+                failed: 'No se pudo generar el CV',
+                required: 'Este campo es obligatorio',
+                too_long: 'El texto es demasiado largo',
+                too_many: 'Hay demasiadas entradas',
+                invalid_email: 'Introduce un email válido',
+                invalid_url: 'Introduce una URL válida (http o https)',
+                invalid_phone: 'Introduce un teléfono válido',
+                invalid_date: 'Fecha no válida',
+                date_order: 'La fecha de fin no puede ser anterior a la de inicio',
+                invalid_code: 'Código de idioma ISO 639 no válido',
+                invalid_level: 'Nivel no válido',
+                conflict: 'Este campo no es compatible con otro ya rellenado',
+                out_of_range: 'Valor fuera del rango permitido',
+                cannot_fit: 'El CV no cabe en ese número de páginas sin reducir demasiado el texto',
+                invalid_archive: 'El archivo no es una exportación de datos de LinkedIn (ZIP)'
             }
         },
         en: {
@@ -90,8 +128,13 @@ const i18n = {
             actions: {
                 preview: 'Preview',
                 export: 'Export PDF',
+                exportDocx: 'Export Word',
+                importLinkedIn: 'Import LinkedIn',
                 add: 'Add',
-                remove: 'Remove'
+                remove: 'Remove',
+                fitNone: 'No fitting',
+                fitOne: 'Fit to 1 page',
+                fitTwo: 'Fit to 2 pages'
             },
             sections: {
                 personal: 'Personal Information',
@@ -135,11 +178,15 @@ const i18n = {
                 institution: 'University, institute, etc.',
                 degree: 'Degree, certification, etc.',
                 skillName: 'Skill (e.g: JavaScript, Leadership)',
-                language: 'Language (e.g: Spanish - Native, English - Advanced)'
+                language: 'Language (e.g: Spanish)',
+                certificate: 'Certificate (e.g: TOEFL, DELE C1)'
             },
             preview: {
                 title: 'CV Preview'
             },
+            import: {
+                warnings: 'CV imported. This data could not be imported:'
+            },
             experience: {
                 title: 'Experience',
                 number: 'Experience'
@@ -157,10 +204,39 @@ const i18n = {
                     'Experto': 'Expert'
                 }
             },
+            languages: {
+                levels: {
+                    '': 'Level',
+                    'A1': 'A1 - Beginner',
+                    'A2': 'A2 - Elementary',
+                    'B1': 'B1 - Intermediate',
+                    'B2': 'B2 - Upper intermediate',
+                    'C1': 'C1 - Advanced',
+                    'C2': 'C2 - Proficient',
+                    'Native': 'Native'
+                }
+            },
             notes: {
                 currentJob: 'Leave empty if this is your current job',
                 currentStudy: 'Leave empty if you are still studying',
                 achievements: 'Honors, relevant projects...'
+            },
+            validation: {
+                failed: 'The CV could not be generated',
+                required: 'This field is required',
+                too_long: 'The text is too long',
+                too_many: 'There are too many entries',
+                invalid_email: 'Enter a valid email address',
+                invalid_url: 'Enter a valid URL (http or https)',
+                invalid_phone: 'Enter a valid phone number',
+                invalid_date: 'Invalid date',
+                date_order: 'The end date cannot be before the start date',
+                invalid_code: 'Invalid ISO 639 language code',
+                invalid_level: 'Invalid level',
+                conflict: 'This field cannot be combined with another filled-in field',
+                out_of_range: 'Value out of the allowed range',
+                cannot_fit: 'The CV does not fit in that many pages without making the text too small',
+                invalid_archive: 'The file is not a LinkedIn data export (ZIP)'
             }
         }
     },
@@ -258,6 +334,14 @@ const i18n = {
             }
         });
 
+        // Update language level options
+        document.querySelectorAll('.language-level option').forEach(option => {
+            const value = option.value;
+            if (this.t('languages.levels')[value] !== undefined) {
+                option.textContent = this.t('languages.levels')[value];
+            }
+        });
+
         // Update notes/hints
         document.querySelectorAll('small').forEach(small => {
             const text = small.textContent.trim();
@@ -0,0 +1,12 @@
+// Package web bundles the templates that are rendered outside the Fiber view
+// engine, so they are available regardless of the working directory.
+package web
+
+import _ "embed"
+
+// CVTemplate is the standalone HTML layout of a CV. It lives outside
+// templates/ because the view engine parses every file in that directory
+// without the translation functions the layout relies on.
+//
+//go:embed cv/cv_template.html
+var CVTemplate string